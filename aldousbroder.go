@@ -0,0 +1,29 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// RectangleAldousBroder creates a maze using the Aldous-Broder algorithm.
+// like Wilson's algorithm, it picks uniformly from all possible perfect mazes, but it is much slower on large grids.
+func RectangleAldousBroder(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, AldousBroderGenerator{}, solve)
+}
+
+// carveAldousBroder carves passages through the grid using the Aldous-Broder algorithm.
+// it walks from neighbor to neighbor at random, removing the wall whenever it steps into
+// a cell that isn't in the maze yet, until every cell has been added.
+func (g *grid) carveAldousBroder(rng *rand.Rand) {
+	cells := g.allCells()
+	current := cells[rng.Intn(len(cells))]
+	current.in = true
+	for remaining := len(cells) - 1; remaining > 0; {
+		next := current.randomNeighbor(rng, g.bias)
+		if !next.in {
+			current.linkTo(next)
+			next.in = true
+			remaining--
+		}
+		current = next
+	}
+}
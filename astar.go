@@ -0,0 +1,129 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// SolveAStar finds the shortest path from the entrance to the exit using A* search
+// with the Manhattan distance to the exit as the heuristic.
+// if there are several gates, it searches from every entrance and uses the distance to the nearest exit.
+// the heuristic never overestimates, so the path is always a shortest one: on a toroidal maze the
+// distance is measured the short way around, and in a weave maze it is halved, since a step through
+// a tunnel crosses two cells.
+// unlike Solve, it always replaces any existing solution.
+// it returns an error if there is no path from an entrance to an exit.
+// the cells on the path are flagged so that the renderers will show them.
+func (r *Rectangle) SolveAStar() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ResetSolution()
+
+	// manhattan returns the estimated number of steps from the cell to the nearest exit
+	wraps, crossings := r.g.wraps(), r.g.hasCrossings()
+	manhattan := func(c *cell) int {
+		estimate := -1
+		for _, exit := range r.exits {
+			rows, cols := abs(c.row-exit.row), abs(c.col-exit.col)
+			if wraps {
+				rows, cols = min(rows, r.g.height-rows), min(cols, r.g.width-cols)
+			}
+			n := rows + cols
+			if crossings {
+				n = (n + 1) / 2
+			}
+			if estimate == -1 || n < estimate {
+				estimate = n
+			}
+		}
+		return estimate
+	}
+
+	// steps is the number of steps on the best known path from the entrance to each cell
+	steps := map[*cell]int{}
+	open := &astarQueue{}
+	for _, entrance := range r.entrances {
+		steps[entrance] = 0
+		heap.Push(open, &astarItem{c: entrance, estimate: manhattan(entrance), seq: open.pushed})
+	}
+	var exit *cell
+	for open.Len() != 0 {
+		current := heap.Pop(open).(*astarItem).c
+		if current.visited {
+			// a shorter path to this cell has already been expanded
+			continue
+		}
+		current.visited = true
+		if current.isExit() {
+			exit = current
+			break
+		}
+
+		for _, neighbor := range current.openNeighbors() {
+			if neighbor.visited {
+				continue
+			}
+			if known, ok := steps[neighbor]; ok && known <= steps[current]+1 {
+				continue
+			}
+			steps[neighbor] = steps[current] + 1
+			neighbor.to = current
+			heap.Push(open, &astarItem{c: neighbor, estimate: steps[neighbor] + manhattan(neighbor), seq: open.pushed})
+		}
+	}
+
+	if exit == nil {
+		return fmt.Errorf("solve: no path from an entrance to an exit")
+	}
+
+	// flag each cell that is on the path between the entrance and the exit
+	r.g.markPath(exit)
+
+	r.solved = true
+	return nil
+}
+
+// astarItem is an entry in the A* priority queue. SolveDijkstra uses the same queue with no heuristic.
+type astarItem struct {
+	c        *cell
+	estimate int // steps from the entrance plus the estimated steps to the exit
+	seq      int // order the item was pushed, used to break ties
+}
+
+// astarQueue implements heap.Interface, ordering items by estimate and then by push order.
+type astarQueue struct {
+	items  []*astarItem
+	pushed int
+}
+
+func (q *astarQueue) Len() int { return len(q.items) }
+
+func (q *astarQueue) Less(i, j int) bool {
+	if q.items[i].estimate != q.items[j].estimate {
+		return q.items[i].estimate < q.items[j].estimate
+	}
+	return q.items[i].seq < q.items[j].seq
+}
+
+func (q *astarQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *astarQueue) Push(x any) {
+	q.items = append(q.items, x.(*astarItem))
+	q.pushed++
+}
+
+func (q *astarQueue) Pop() any {
+	item := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return item
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
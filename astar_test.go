@@ -0,0 +1,82 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkPath fails the test unless the path starts at an entrance, ends at an exit,
+// and only steps between cells that are open to each other.
+func checkPath(t *testing.T, r *Rectangle, path [][2]int) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatalf("path: want cells, got none")
+	}
+	first, _ := r.g.at(path[0][0], path[0][1])
+	last, _ := r.g.at(path[len(path)-1][0], path[len(path)-1][1])
+	if !first.isEntrance() || !last.isExit() {
+		t.Fatalf("path: want entrance to exit, got %v to %v", path[0], path[len(path)-1])
+	}
+	for n := 1; n < len(path); n++ {
+		from, _ := r.g.at(path[n-1][0], path[n-1][1])
+		to, _ := r.g.at(path[n][0], path[n][1])
+		if !from.isOpenTo(to) {
+			t.Fatalf("path: step %d: %v is not open to %v", n, path[n-1], path[n])
+		}
+	}
+}
+
+func TestSolveAStarShortest(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		r, err := RectangleMazeWith(15, 20, WilsonGenerator{}, false, WithSeed(seed))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		// braiding adds loops, so the first path found is not always the shortest
+		r.Braid(0.8)
+		if err := r.SolveAStar(); err != nil {
+			t.Fatalf("seed %d: SolveAStar: %v", seed, err)
+		}
+		path := r.SolutionPath()
+		checkPath(t, r, path)
+
+		exit := r.exits[0]
+		if want := r.DistanceField()[exit.row][exit.col] + 1; len(path) != want {
+			t.Errorf("seed %d: SolveAStar: path length: want %d, got %d", seed, want, len(path))
+		}
+	}
+}
+
+func TestSolveAStarWeaveAndToroidal(t *testing.T) {
+	// a step through a tunnel crosses two cells and a step across the edge of a toroidal maze
+	// crosses the whole grid, so the plain Manhattan distance would overestimate on these mazes
+	for _, tc := range []struct {
+		name string
+		gen  Generator
+		opts []Option
+	}{
+		{"weave", WeaveGenerator{}, nil},
+		{"toroidal", WilsonGenerator{}, []Option{withToroidal()}},
+	} {
+		for seed := int64(1); seed <= 20; seed++ {
+			r, err := RectangleMazeWith(12, 12, tc.gen, false, append(tc.opts, WithSeed(seed))...)
+			if err != nil {
+				t.Fatalf("%s: RectangleMazeWith: %v", tc.name, err)
+			}
+			r.g.braid(1, rand.New(rand.NewSource(seed)))
+			if err := r.SolveBFS(); err != nil {
+				t.Fatalf("%s: seed %d: SolveBFS: %v", tc.name, seed, err)
+			}
+			want := r.SolutionLength()
+			if err := r.SolveAStar(); err != nil {
+				t.Fatalf("%s: seed %d: SolveAStar: %v", tc.name, seed, err)
+			}
+			checkPath(t, r, r.SolutionPath())
+			if got := r.SolutionLength(); got != want {
+				t.Errorf("%s: seed %d: SolveAStar: path length: want %d, got %d", tc.name, seed, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// GenerateBatch creates n mazes using Wilson's algorithm, spreading the work across all CPUs.
+// each maze gets its own source, seeded from the top-level seed, so the same seed always
+// returns the same mazes in the same order. it returns nil if the dimensions are not valid.
+func GenerateBatch(n, height, width int, seed int64) []*Rectangle {
+	if n < 1 || validateDimensions(height, width) != nil {
+		return nil
+	}
+
+	// derive the seeds up front so that they don't depend on the order the workers run in
+	seeds := make([]int64, n)
+	rng := rand.New(rand.NewSource(seed))
+	for i := range seeds {
+		seeds[i] = rng.Int63()
+	}
+
+	mazes := make([]*Rectangle, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(n, runtime.NumCPU()); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// the dimensions were validated above and there is no deadline, so this can't fail
+				mazes[i], _ = RectangleMazeWithSeed(height, width, WilsonGenerator{}, seeds[i], false)
+			}
+		}()
+	}
+	for i := range mazes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return mazes
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+func TestGenerateBatch(t *testing.T) {
+	batch := GenerateBatch(20, 6, 7, 99)
+	if len(batch) != 20 {
+		t.Fatalf("GenerateBatch: want 20 mazes, got %d", len(batch))
+	}
+	again := GenerateBatch(20, 6, 7, 99)
+	seen := map[string]bool{}
+	for i, r := range batch {
+		if r == nil || !r.IsPerfect() {
+			t.Fatalf("GenerateBatch: maze %d: want a perfect maze", i)
+		}
+		// the batch is in the same order every time, whichever worker carved each maze
+		text := renderText(t, r)
+		if text != renderText(t, again[i]) {
+			t.Errorf("GenerateBatch: maze %d: same seed gave a different maze", i)
+		}
+		seen[text] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("GenerateBatch: want different mazes in the batch, got %d", len(seen))
+	}
+
+	if other := GenerateBatch(20, 6, 7, 100); renderText(t, other[0]) == renderText(t, batch[0]) {
+		t.Errorf("GenerateBatch: different seeds gave the same first maze")
+	}
+	if got := GenerateBatch(0, 6, 7, 99); got != nil {
+		t.Errorf("GenerateBatch: n = 0: want nil, got %d mazes", len(got))
+	}
+	if got := GenerateBatch(5, 1, 7, 99); got != nil {
+		t.Errorf("GenerateBatch: 1 x 7: want nil, got %d mazes", len(got))
+	}
+}
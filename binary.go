@@ -0,0 +1,231 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// the binary format is, in order:
+//
+//	magic     4 bytes, "MAZE"
+//	version   1 byte, currently 1
+//	flags     1 byte, bit 0 is set if the maze was solved and bit 1 is set if it has masked cells
+//	height    uint32, big endian
+//	width     uint32, big endian
+//	entrances uint32 count, followed by a uint32 row, column, and side for each entrance
+//	exits     uint32 count, followed by a uint32 row, column, and side for each exit
+//	mask      only if flag bit 1 is set: 1 bit per cell, in row-major order, set for masked cells.
+//	          it is packed like the walls.
+//	walls     2 bits per cell, in row-major order, packed from the high bit of each byte down.
+//	          the first bit is the east wall and the second is the south wall.
+//	          the last byte is padded with zero bits.
+//
+// the side of a gate is the Direction of the outer wall that is open.
+// north and west walls are not stored since they are the south and east walls of the neighboring cells.
+// outer walls are not stored either; they are closed except on the side of each entrance and exit.
+// walls that face a masked cell count as outer walls.
+// cell weights are not saved. weave mazes can't be written at all, since the walls around
+// a crossing don't match the walls of its neighbors, and neither can toroidal mazes, since the
+// format has no way to mark the walls on the edges as passages that wrap around.
+const (
+	binaryMagic   = "MAZE"
+	binaryVersion = 1
+	// binaryMaxCells limits the size of the grid that ReadBinary will allocate
+	binaryMaxCells = 1 << 30
+)
+
+// WriteBinary writes the maze in a compact binary format that stores two bits per cell.
+// see ReadBinary for reading it back. it returns an error for weave and toroidal mazes.
+func (r *Rectangle) WriteBinary(w io.Writer) error {
+	if r.g.hasCrossings() {
+		return fmt.Errorf("binary: weave crossings can't be saved")
+	} else if r.g.wraps() {
+		return fmt.Errorf("binary: toroidal mazes can't be saved")
+	}
+	bw := bufio.NewWriter(w)
+
+	// write the header
+	var flags byte
+	if r.solved {
+		flags |= 1
+	}
+	masked := false
+	for _, c := range r.g.allCells() {
+		masked = masked || c.masked
+	}
+	if masked {
+		flags |= 2
+	}
+	bw.WriteString(binaryMagic)
+	bw.WriteByte(binaryVersion)
+	bw.WriteByte(flags)
+	header := []uint32{uint32(r.g.height), uint32(r.g.width)}
+	for _, gates := range [][]*cell{r.entrances, r.exits} {
+		header = append(header, uint32(len(gates)))
+		for _, c := range gates {
+			header = append(header, uint32(c.row), uint32(c.col), uint32(c.gate))
+		}
+	}
+	if err := binary.Write(bw, binary.BigEndian, header); err != nil {
+		return err
+	}
+
+	// pack the mask, if there is one
+	if masked {
+		mask := make([]byte, (r.g.height*r.g.width+7)/8)
+		for bit, c := range r.g.allCells() {
+			if c.masked {
+				mask[bit/8] |= 0x80 >> (bit % 8)
+			}
+		}
+		bw.Write(mask)
+	}
+
+	// pack the east and south walls of every cell
+	walls := make([]byte, (r.g.height*r.g.width*2+7)/8)
+	bit := 0
+	for _, c := range r.g.allCells() {
+		for _, wall := range []bool{c.walls.east, c.walls.south} {
+			if wall {
+				walls[bit/8] |= 0x80 >> (bit % 8)
+			}
+			bit++
+		}
+	}
+	bw.Write(walls)
+
+	return bw.Flush()
+}
+
+// ReadBinary creates a maze from data written by WriteBinary.
+// if the maze was solved when it was written, it is solved again after reading.
+func ReadBinary(rd io.Reader) (*Rectangle, error) {
+	br := bufio.NewReader(rd)
+
+	// read and validate the header
+	prefix := make([]byte, len(binaryMagic)+2)
+	if _, err := io.ReadFull(br, prefix); err != nil {
+		return nil, fmt.Errorf("binary: %w", err)
+	} else if string(prefix[:len(binaryMagic)]) != binaryMagic {
+		return nil, fmt.Errorf("binary: not a maze file")
+	}
+	version := prefix[len(binaryMagic)]
+	if version != binaryVersion {
+		return nil, fmt.Errorf("binary: unsupported version %d", version)
+	}
+	solved, masked := prefix[len(binaryMagic)+1]&1 != 0, prefix[len(binaryMagic)+1]&2 != 0
+
+	var dimensions [2]uint32
+	if err := binary.Read(br, binary.BigEndian, &dimensions); err != nil {
+		return nil, fmt.Errorf("binary: %w", err)
+	}
+	height, width := int(dimensions[0]), int(dimensions[1])
+	if err := validateDimensions(height, width); err != nil {
+		return nil, fmt.Errorf("binary: %w", err)
+	} else if uint64(dimensions[0])*uint64(dimensions[1]) > binaryMaxCells {
+		return nil, fmt.Errorf("binary: maze of %d x %d cells is too large", height, width)
+	}
+	g := createGrid(height, width)
+
+	// readGates reads a count followed by the coordinates and side of each gate.
+	// the sides are collected in the order the gates are read and checked once the mask is applied.
+	var sides []uint32
+	readGates := func() ([]*cell, error) {
+		var count uint32
+		if err := binary.Read(br, binary.BigEndian, &count); err != nil {
+			return nil, err
+		} else if count < 1 || int(count) > 2*(height+width) {
+			return nil, fmt.Errorf("invalid gate count %d", count)
+		}
+		var gates []*cell
+		for n := uint32(0); n < count; n++ {
+			var gate [3]uint32
+			if err := binary.Read(br, binary.BigEndian, &gate); err != nil {
+				return nil, err
+			}
+			row, col, side := int(gate[0]), int(gate[1]), gate[2]
+			if !g.inBounds(row, col) {
+				return nil, fmt.Errorf("cell (%d, %d) is out of bounds", row, col)
+			}
+			gates = append(gates, g.cells[row][col])
+			sides = append(sides, side)
+		}
+		return gates, nil
+	}
+	r := &Rectangle{g: g}
+	var err error
+	if r.entrances, err = readGates(); err != nil {
+		return nil, fmt.Errorf("binary: entrance: %w", err)
+	} else if r.exits, err = readGates(); err != nil {
+		return nil, fmt.Errorf("binary: exit: %w", err)
+	}
+
+	// restore the shape of the maze before opening the gates, since the gates of a masked maze
+	// can open onto masked cells instead of the edges of the grid
+	if masked {
+		bits := make([]byte, (height*width+7)/8)
+		if _, err := io.ReadFull(br, bits); err != nil {
+			return nil, fmt.Errorf("binary: mask: %w", err)
+		}
+		mask := make([][]bool, height)
+		for row := range mask {
+			mask[row] = make([]bool, width)
+			for col := range mask[row] {
+				bit := row*width + col
+				mask[row][col] = bits[bit/8]&(0x80>>(bit%8)) == 0
+			}
+		}
+		if err := g.applyMask(mask); err != nil {
+			return nil, fmt.Errorf("binary: %w", err)
+		}
+	}
+	for n, c := range append(append([]*cell{}, r.entrances...), r.exits...) {
+		// a gate must open through an outer wall of a cell that is in the maze
+		if c.masked {
+			return nil, fmt.Errorf("binary: gate (%d, %d) is masked", c.row, c.col)
+		} else if side := sides[n]; side > uint32(West) || c.neighbor(Direction(side)) != nil {
+			return nil, fmt.Errorf("binary: gate (%d, %d): invalid side %d", c.row, c.col, side)
+		}
+		c.openGate(Direction(sides[n]))
+	}
+	for _, c := range r.entrances {
+		c.entrance = true
+	}
+	for _, c := range r.exits {
+		c.exit = true
+	}
+
+	// unpack the walls, copying each one to the neighbor on the other side
+	walls := make([]byte, (height*width*2+7)/8)
+	if _, err := io.ReadFull(br, walls); err != nil {
+		return nil, fmt.Errorf("binary: walls: %w", err)
+	}
+	bit := 0
+	for _, c := range g.allCells() {
+		c.walls.east = walls[bit/8]&(0x80>>(bit%8)) != 0
+		c.walls.south = walls[(bit+1)/8]&(0x80>>((bit+1)%8)) != 0
+		bit += 2
+		if c.neighbors.east != nil {
+			c.neighbors.east.walls.west = c.walls.east
+		}
+		if c.neighbors.south != nil {
+			c.neighbors.south.walls.north = c.walls.south
+		}
+		c.in = !c.masked
+	}
+
+	// close the outer walls and open the gates
+	r.SealBorder()
+
+	if solved {
+		if err := r.Solve(); err != nil {
+			return nil, fmt.Errorf("binary: %w", err)
+		}
+	}
+
+	return r, nil
+}
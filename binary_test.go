@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	r, err := RectangleMazeWith(30, 40, WilsonGenerator{}, true, WithSeed(12))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	var b bytes.Buffer
+	if err := r.WriteBinary(&b); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	// the header holds one entrance and one exit, and the walls take two bits per cell
+	if want := 4 + 1 + 1 + 4 + 4 + 2*(4+3*4) + (2*30*40+7)/8; b.Len() != want {
+		t.Errorf("WriteBinary: want %d bytes, got %d", want, b.Len())
+	}
+	data := bytes.Clone(b.Bytes())
+
+	loaded, err := ReadBinary(&b)
+	if err != nil {
+		t.Fatalf("ReadBinary: %v", err)
+	}
+	// the solution is saved too, so the text shows the same path
+	if want, got := renderText(t, r), renderText(t, loaded); got != want {
+		t.Errorf("ReadBinary: want\n%s\ngot\n%s", want, got)
+	}
+	if !loaded.IsPerfect() {
+		t.Errorf("ReadBinary: maze is not perfect")
+	}
+
+	// the binary format is much smaller than JSON
+	js, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if len(data)*10 > len(js) {
+		t.Errorf("WriteBinary: %d bytes is not much smaller than %d bytes of JSON", len(data), len(js))
+	}
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"magic", append([]byte("MAZY"), data[4:]...)},
+		{"version", append(append([]byte("MAZE"), 99), data[5:]...)},
+		{"truncated", data[:len(data)-1]},
+	} {
+		if _, err := ReadBinary(bytes.NewReader(tc.data)); err == nil {
+			t.Errorf("ReadBinary: %s: want error, got nil", tc.name)
+		}
+	}
+}
+
+func TestBinaryRoundTripMasked(t *testing.T) {
+	// a hole in the middle and two corners cut off, so some of the walls facing masked cells are inside the grid
+	mask := make([][]bool, 7)
+	for row := range mask {
+		mask[row] = make([]bool, 9)
+		for col := range mask[row] {
+			mask[row][col] = row < 2 || row > 4 || col < 3 || col > 5
+		}
+	}
+	mask[0][0], mask[6][8] = false, false
+	for _, solve := range []bool{false, true} {
+		r, err := RectangleMazeWith(7, 9, WilsonGenerator{}, solve, withMask(mask), WithSeed(3))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		var b bytes.Buffer
+		if err := r.WriteBinary(&b); err != nil {
+			t.Fatalf("WriteBinary: %v", err)
+		}
+		loaded, err := ReadBinary(&b)
+		if err != nil {
+			t.Fatalf("ReadBinary: %v", err)
+		}
+		for _, c := range r.g.allCells() {
+			if loaded.g.cells[c.row][c.col].masked != c.masked {
+				t.Errorf("ReadBinary: (%d, %d): want masked %v", c.row, c.col, c.masked)
+			}
+		}
+		if want, got := renderText(t, r), renderText(t, loaded); got != want {
+			t.Errorf("ReadBinary: solved %v: want\n%s\ngot\n%s", solve, want, got)
+		}
+		if loaded.solved != solve {
+			t.Errorf("ReadBinary: want solved %v, got %v", solve, loaded.solved)
+		}
+		if err := loaded.Solve(); err != nil {
+			t.Errorf("ReadBinary: Solve: %v", err)
+		}
+	}
+}
+
+func TestBinaryUnsupported(t *testing.T) {
+	toroidal, err := RectangleToroidalMaze(5, 5, false)
+	if err != nil {
+		t.Fatalf("RectangleToroidalMaze: %v", err)
+	}
+	// neither the tunnels nor the passages that wrap around can be stored, so the maze would come back different
+	for _, tc := range []struct {
+		name string
+		r    *Rectangle
+	}{
+		{"toroidal", toroidal},
+		{"weave", weaveMaze(t)},
+	} {
+		var b bytes.Buffer
+		if err := tc.r.WriteBinary(&b); err == nil {
+			t.Errorf("WriteBinary: %s: want error, got nil", tc.name)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// RectangleBinaryTree creates a maze using the binary tree algorithm.
+// every cell is linked to its northern or eastern neighbor, which biases the maze
+// toward the north-east and always leaves the top row and right column fully open.
+func RectangleBinaryTree(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, BinaryTreeGenerator{}, solve)
+}
+
+// carveBinaryTree links every cell to either its northern or eastern neighbor, chosen at random.
+// cells on the top row can only link east and cells in the right column can only link north.
+func (g *grid) carveBinaryTree(rng *rand.Rand) {
+	for _, c := range g.allCells() {
+		var candidates []*cell
+		if c.neighbors.north != nil {
+			candidates = append(candidates, c.neighbors.north)
+		}
+		if c.neighbors.east != nil {
+			candidates = append(candidates, c.neighbors.east)
+		}
+		if len(candidates) != 0 {
+			c.linkTo(candidates[rng.Intn(len(candidates))])
+		}
+		c.in = true
+	}
+}
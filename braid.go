@@ -0,0 +1,80 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Braid removes dead ends from the maze by opening a wall from the dead end to a random neighbor.
+// percentage is the fraction, from 0 to 1, of the dead ends to remove. removing dead ends creates loops,
+// so the maze will no longer be perfect. any existing solution is cleared.
+// in a weave maze, walls are never opened into a crossing, so a dead end whose only closed walls
+// face crossings is left alone.
+func (r *Rectangle) Braid(percentage float64) {
+	r.g.braid(percentage, rand.New(rand.NewSource(rand.Int63())))
+	r.ResetSolution()
+}
+
+// AddLoop opens the wall on the given side of the cell to create a loop. the cells on either side of the wall
+// must already be connected by another path, so opening it always adds exactly one cycle. any existing solution is cleared.
+// it returns an error if the cell is out of bounds, there is no neighbor in that direction, the wall is already open,
+// or the two cells aren't connected.
+func (r *Rectangle) AddLoop(row, col int, dir Direction) error {
+	c, ok := r.g.at(row, col)
+	if !ok {
+		return fmt.Errorf("cell (%d, %d) is out of bounds", row, col)
+	}
+	neighbor := c.neighbor(dir)
+	if neighbor == nil {
+		return fmt.Errorf("cell (%d, %d) has no neighbor to the %s", row, col, dir)
+	} else if !c.wall(dir) {
+		return fmt.Errorf("cell (%d, %d): the wall to the %s is already open", row, col, dir)
+	} else if r.g.distancesFrom(c)[neighbor.row][neighbor.col] == -1 {
+		return fmt.Errorf("cell (%d, %d) is not connected to its neighbor to the %s", row, col, dir)
+	}
+	c.linkTo(neighbor)
+	r.ResetSolution()
+	return nil
+}
+
+// braid removes the given fraction of dead ends from the grid, using rng to choose them.
+func (g *grid) braid(percentage float64, rng *rand.Rand) {
+	var deadEnds []*cell
+	for _, c := range g.allCells() {
+		if c.isDeadEnd() && !c.under {
+			deadEnds = append(deadEnds, c)
+		}
+	}
+	rng.Shuffle(len(deadEnds), func(i, j int) {
+		deadEnds[i], deadEnds[j] = deadEnds[j], deadEnds[i]
+	})
+
+	n := int(float64(len(deadEnds))*percentage + 0.5)
+	for _, c := range deadEnds[:max(0, min(n, len(deadEnds)))] {
+		// opening the wall of an earlier dead end may have fixed this one already
+		if !c.isDeadEnd() {
+			continue
+		}
+		// prefer neighbors that are also dead ends since linking them removes two at once
+		// a crossing keeps the walls on the sides of its tunnel, so opening one would join the tunnel
+		// to the bridge above it
+		var closed, closedDeadEnds []*cell
+		for _, neighbor := range c.neighborhood {
+			if neighbor.under || c.isOpenTo(neighbor) {
+				continue
+			}
+			closed = append(closed, neighbor)
+			if neighbor.isDeadEnd() {
+				closedDeadEnds = append(closedDeadEnds, neighbor)
+			}
+		}
+		if len(closedDeadEnds) != 0 {
+			closed = closedDeadEnds
+		}
+		if len(closed) != 0 {
+			c.linkTo(closed[rng.Intn(len(closed))])
+		}
+	}
+}
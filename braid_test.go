@@ -0,0 +1,126 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// deadEnds returns the number of dead ends in the maze.
+func deadEnds(r *Rectangle) int {
+	n := 0
+	for _, c := range r.g.allCells() {
+		if c.isDeadEnd() {
+			n++
+		}
+	}
+	return n
+}
+
+func TestBraid(t *testing.T) {
+	for _, tc := range []struct {
+		percentage float64
+		check      func(before, after int) bool
+	}{
+		{0, func(before, after int) bool { return after == before }},
+		{0.5, func(before, after int) bool { return after < before && after != 0 }},
+		{1, func(before, after int) bool { return after == 0 }},
+	} {
+		r, err := RectangleMazeWith(12, 12, WilsonGenerator{}, true, WithSeed(9))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		before := deadEnds(r)
+		r.Braid(tc.percentage)
+		if after := deadEnds(r); !tc.check(before, after) {
+			t.Errorf("Braid(%g): dead ends: %d before, %d after", tc.percentage, before, after)
+		}
+		// braiding changes the maze, so the old solution is cleared
+		if r.SolutionPath() != nil {
+			t.Errorf("Braid(%g): want the solution cleared", tc.percentage)
+		}
+	}
+}
+
+func TestAddLoop(t *testing.T) {
+	r := loopMaze(t)
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	// the center cell is a dead end off the western column and is connected to its eastern neighbor the long way
+	if err := r.AddLoop(1, 1, East); err != nil {
+		t.Fatalf("AddLoop: %v", err)
+	}
+	if !r.g.cells[1][1].isOpenTo(r.g.cells[1][2]) {
+		t.Errorf("AddLoop: want the center open to the east")
+	}
+	if r.SolutionPath() != nil {
+		t.Errorf("AddLoop: want the solution cleared")
+	}
+
+	for _, tc := range []struct {
+		name     string
+		row, col int
+		dir      Direction
+	}{
+		{"out of bounds", 3, 0, North},
+		{"no neighbor", 2, 0, South},
+		{"already open", 0, 0, East},
+	} {
+		if err := r.AddLoop(tc.row, tc.col, tc.dir); err == nil {
+			t.Errorf("AddLoop: %s: want error, got nil", tc.name)
+		}
+	}
+
+	// the exit is walled off, so opening a wall to it would join two parts instead of adding a loop
+	sealed := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 1},
+		passage{{0, 0}, {0, 1}}, passage{{0, 0}, {1, 0}},
+	)
+	if err := sealed.AddLoop(0, 1, South); err == nil {
+		t.Errorf("AddLoop: not connected: want error, got nil")
+	}
+}
+
+func TestAddLoopPerfect(t *testing.T) {
+	r, err := RectangleMazeWith(8, 8, WilsonGenerator{}, true, WithSeed(6))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	if !r.IsPerfect() {
+		t.Fatalf("IsPerfect: want true before AddLoop, got false")
+	}
+	// every pair of cells in a perfect maze is connected, so any closed inner wall can be opened
+	c := r.g.cells[4][4]
+	dir := North
+	for dir <= West && (c.neighbor(dir) == nil || !c.wall(dir)) {
+		dir++
+	}
+	if dir > West {
+		t.Fatalf("AddLoop: (4, 4) has no closed inner walls")
+	}
+	if err := r.AddLoop(4, 4, dir); err != nil {
+		t.Fatalf("AddLoop %v: %v", dir, err)
+	}
+	if !c.isOpenTo(c.neighbor(dir)) || c.neighbor(dir).wall(dir.opposite()) {
+		t.Errorf("AddLoop %v: want both sides of the wall open", dir)
+	}
+	if r.IsPerfect() {
+		t.Errorf("IsPerfect: want false after AddLoop, got true")
+	}
+}
+
+func TestBraidWeave(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		r, err := RectangleMazeWith(10, 10, WeaveGenerator{}, false, WithSeed(seed))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		r.g.braid(1, rand.New(rand.NewSource(seed)))
+		// braiding opens walls, but never the walls of a crossing, so every tunnel still runs beneath its bridge
+		checkCrossings(t, r)
+		if err := r.Solve(); err != nil {
+			t.Errorf("seed %d: Solve: %v", seed, err)
+		}
+	}
+}
@@ -5,8 +5,7 @@ package maze
 import "math/rand"
 
 type cell struct {
-	row, col int
-	// todo: implement hex grid
+	row, col  int
 	neighbors struct {
 		north *cell
 		east  *cell
@@ -27,6 +26,16 @@ type cell struct {
 	exit bool
 	// in is set to true if the cell has been added to the maze
 	in bool
+	// masked is set to true if the cell is excluded from the maze
+	masked bool
+	// under is set to true if a passage tunnels beneath the cell.
+	// the tunnel runs between the two sides of the cell that still have walls.
+	under bool
+	// gate is the side whose outer wall was opened when the cell was made an entrance or exit.
+	// the border can then be sealed without moving the gate to a different wall.
+	gate Direction
+	// weight is the cost of stepping into the cell when solving with SolveDijkstra
+	weight int
 	// onPath is set if the cell is on the path between the entrance and the exit
 	onPath bool
 	// visited is set to true if the cell has been visited while trying to solve
@@ -69,14 +78,190 @@ func (c *cell) westIsOpen() bool {
 	return c.neighbors.west != nil && !c.walls.west
 }
 
-// randomNeighbor returns a neighboring cell at random.
+// openNeighbors returns the neighbors that can be reached from the cell without crossing a wall.
+// a neighbor on the far side of a tunnel counts as an open neighbor.
+// the neighbors are always returned in searchOrder.
+func (c *cell) openNeighbors() []*cell {
+	var neighbors []*cell
+	for _, dir := range searchOrder {
+		if neighbor := c.step(dir); neighbor != nil {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+	return neighbors
+}
+
+// step returns the cell reached by leaving the cell in the given direction, or nil if there is a wall.
+// if the passage leads into a tunnel beneath the neighbor, the step comes out on the far side of it.
+func (c *cell) step(dir Direction) *cell {
+	neighbor := c.neighbor(dir)
+	if neighbor == nil || c.wall(dir) {
+		return nil
+	}
+	if neighbor.under && neighbor.wall(dir.opposite()) {
+		return neighbor.neighbor(dir)
+	}
+	return neighbor
+}
+
+// isOpenTo returns true if the other cell is a neighbor and there is no wall between them.
+func (c *cell) isOpenTo(other *cell) bool {
+	for _, neighbor := range c.openNeighbors() {
+		if neighbor == other {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeadEnd returns true if the cell has exactly one open passage to a neighbor.
+func (c *cell) isDeadEnd() bool {
+	return len(c.openNeighbors()) == 1
+}
+
+// neighbor returns the neighboring cell in the given direction.
+// it returns nil if the cell is on the edge of the grid, unless the grid wraps around.
+func (c *cell) neighbor(dir Direction) *cell {
+	switch dir {
+	case North:
+		return c.neighbors.north
+	case East:
+		return c.neighbors.east
+	case South:
+		return c.neighbors.south
+	case West:
+		return c.neighbors.west
+	}
+	return nil
+}
+
+// wrapsTo returns the direction of the other cell if it is a neighbor that isn't next to this cell in the grid.
+// that only happens on a toroidal grid, where the cells on an edge are linked to the cells on the opposite edge.
+func (c *cell) wrapsTo(other *cell) (Direction, bool) {
+	for _, dir := range []Direction{North, East, South, West} {
+		if c.neighbor(dir) == other && abs(c.row-other.row)+abs(c.col-other.col) != 1 {
+			return dir, true
+		}
+	}
+	return North, false
+}
+
+// wall returns true if there is a wall on the given side of the cell.
+func (c *cell) wall(dir Direction) bool {
+	switch dir {
+	case North:
+		return c.walls.north
+	case East:
+		return c.walls.east
+	case South:
+		return c.walls.south
+	case West:
+		return c.walls.west
+	}
+	return true
+}
+
+// setWall sets the wall flag on the given side of the cell.
+// it does not update the neighboring cell.
+func (c *cell) setWall(dir Direction, wall bool) {
+	switch dir {
+	case North:
+		c.walls.north = wall
+	case East:
+		c.walls.east = wall
+	case South:
+		c.walls.south = wall
+	case West:
+		c.walls.west = wall
+	}
+}
+
+// openGate removes the wall on the first side, in the order given, that doesn't have a neighbor,
+// and records that side as the cell's gate. it returns false if the cell isn't on the edge of the grid.
+func (c *cell) openGate(dirs ...Direction) bool {
+	for _, dir := range dirs {
+		if c.neighbor(dir) == nil {
+			c.setWall(dir, false)
+			c.gate = dir
+			return true
+		}
+	}
+	return false
+}
+
+// linkTo removes the walls between the cell and its neighbor.
+// it returns false, and leaves the walls alone, if the other cell is not a neighbor.
+func (c *cell) linkTo(other *cell) bool {
+	if c.neighbors.north == other {
+		c.walls.north = false
+		other.walls.south = false
+	} else if c.neighbors.east == other {
+		c.walls.east = false
+		other.walls.west = false
+	} else if c.neighbors.south == other {
+		c.walls.south = false
+		other.walls.north = false
+	} else if c.neighbors.west == other {
+		c.walls.west = false
+		other.walls.east = false
+	} else {
+		return false
+	}
+	return true
+}
+
+// unlinkFrom restores the walls between the cell and its neighbor.
+// it returns false, and leaves the walls alone, if the other cell is not a neighbor.
+func (c *cell) unlinkFrom(other *cell) bool {
+	if c.neighbors.north == other {
+		c.walls.north = true
+		other.walls.south = true
+	} else if c.neighbors.east == other {
+		c.walls.east = true
+		other.walls.west = true
+	} else if c.neighbors.south == other {
+		c.walls.south = true
+		other.walls.north = true
+	} else if c.neighbors.west == other {
+		c.walls.west = true
+		other.walls.east = true
+	} else {
+		return false
+	}
+	return true
+}
+
+// randomNeighbor returns a neighboring cell at random, favoring directions with larger weights.
 // if the cell is on an edge, the set won't include the walls.
-func (c *cell) randomNeighbor() *cell {
-	// pick a random direction
-	direction := rand.Intn(len(c.neighborhood))
-	rn := c.neighborhood[direction]
-	if rn == nil {
-		panic("assert(rn != nil)")
-	}
-	return rn
+// the neighborhood only ever holds real cells, and every grid is at least 2 x 2 (masks are checked
+// for connected cells), so there is always at least one neighbor to pick from.
+func (c *cell) randomNeighbor(rng *rand.Rand, weights DirectionWeights) *cell {
+	if weights.isUniform() {
+		return c.neighborhood[rng.Intn(len(c.neighborhood))]
+	}
+	total := 0.0
+	for _, neighbor := range c.neighborhood {
+		total += weights.of(c.directionTo(neighbor))
+	}
+	if total <= 0 {
+		// none of the neighbors can be picked, so fall back to a uniform pick
+		return c.neighborhood[rng.Intn(len(c.neighborhood))]
+	}
+	pick := rng.Float64() * total
+	for _, neighbor := range c.neighborhood {
+		if pick -= weights.of(c.directionTo(neighbor)); pick < 0 {
+			return neighbor
+		}
+	}
+	return c.neighborhood[len(c.neighborhood)-1]
+}
+
+// directionTo returns the direction of a neighboring cell.
+func (c *cell) directionTo(other *cell) Direction {
+	for _, dir := range []Direction{North, East, South, West} {
+		if c.neighbor(dir) == other {
+			return dir
+		}
+	}
+	return North
 }
@@ -0,0 +1,42 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+func TestCellLinkTo(t *testing.T) {
+	g := createGrid(3, 3)
+	center := g.cells[1][1]
+	for _, dir := range []Direction{North, East, South, West} {
+		neighbor := center.neighbor(dir)
+		if !center.linkTo(neighbor) {
+			t.Fatalf("linkTo %v: want true, got false", dir)
+		}
+		if center.wall(dir) || neighbor.wall(dir.opposite()) {
+			t.Errorf("linkTo %v: want both walls removed", dir)
+		}
+		if !center.isOpenTo(neighbor) || !neighbor.isOpenTo(center) {
+			t.Errorf("linkTo %v: want the cells open to each other", dir)
+		}
+		if !center.unlinkFrom(neighbor) {
+			t.Fatalf("unlinkFrom %v: want true, got false", dir)
+		}
+		if !center.wall(dir) || !neighbor.wall(dir.opposite()) {
+			t.Errorf("unlinkFrom %v: want both walls restored", dir)
+		}
+	}
+
+	// the corners aren't neighbors of each other, so nothing changes
+	corner, other := g.cells[0][0], g.cells[2][2]
+	if corner.linkTo(other) {
+		t.Errorf("linkTo: not a neighbor: want false, got true")
+	}
+	if corner.linkTo(g.cells[1][1]) {
+		t.Errorf("linkTo: diagonal: want false, got true")
+	}
+	for _, dir := range []Direction{North, East, South, West} {
+		if !corner.wall(dir) || !other.wall(dir) {
+			t.Errorf("linkTo: not a neighbor: want the %v walls left alone", dir)
+		}
+	}
+}
@@ -4,8 +4,10 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"github.com/mdhender/maze"
+	"io"
 	"log"
 	"math/rand"
 	"os"
@@ -29,16 +31,32 @@ func main() {
 	flag.StringVar(&svgSolvedFile, "svg-solved", svgSolvedFile, "optional name of SVG image file with solution")
 	var txtFile string
 	flag.StringVar(&txtFile, "text", txtFile, "optional name of text file to render")
+	var jsonFile string
+	flag.StringVar(&jsonFile, "json", jsonFile, "optional name of JSON file to write the maze to")
 	var version bool
 	flag.BoolVar(&version, "version", version, "print version and exit")
+	solver := "dfs"
+	flag.StringVar(&solver, "solver", solver, "algorithm used to solve the maze (dfs, bfs, astar, or dijkstra)")
+	var verbose bool
+	flag.BoolVar(&verbose, "verbose", verbose, "log progress while solving the maze")
 
 	flag.Parse()
 
+	if verbose {
+		maze.SetLogger(log.Default())
+	}
+
 	if version {
 		log.Println("maze: 1.0.0")
 		return
 	}
 
+	switch solver {
+	case "dfs", "bfs", "astar", "dijkstra":
+	default:
+		log.Fatalf("maze: unknown solver %q\n", solver)
+	}
+
 	// set seed only if we're testing changes
 	if testSeed != 0 {
 		log.Printf("maze: using seed %d\n", testSeed)
@@ -52,70 +70,106 @@ func main() {
 	}
 	log.Printf("maze: created %5d x %5d maze in %v\n", height, width, time.Now().Sub(started))
 
-	if txtFile != "" {
-		started = time.Now()
-		w, err := os.OpenFile(txtFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Fatal(err)
-		} else if err = rg.RenderText(w); err != nil {
-			log.Fatal(err)
-		} else if err = w.Close(); err != nil {
-			log.Fatal(err)
+	files := outputs{
+		text:      txtFile,
+		json:      jsonFile,
+		png:       pngFile,
+		pngSolved: pngSolvedFile,
+		svg:       svgFile,
+		svgSolved: svgSolvedFile,
+	}
+	if err := render(rg, scale, solver, files); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// outputs holds the names of the files to render the maze to. empty names are skipped.
+type outputs struct {
+	text, json     string
+	png, pngSolved string
+	svg, svgSolved string
+}
+
+// render writes the maze to each of the output files.
+// the unsolved images are written first. the maze is then solved once, with the named solver,
+// and the solved images are written from that solution.
+func render(rg *maze.Rectangle, scale int, solver string, files outputs) error {
+	if files.text != "" {
+		if err := writeFile(files.text, rg.RenderText); err != nil {
+			return err
 		}
-		log.Printf("maze: created %s in %v\n", txtFile, time.Now().Sub(started))
 	}
 
-	if pngFile != "" {
-		started = time.Now()
-		w, err := os.OpenFile(pngFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if files.json != "" {
+		started := time.Now()
+		data, err := json.Marshal(rg)
 		if err != nil {
-			log.Fatal(err)
-		} else if err = rg.RenderPNG(w, scale); err != nil {
-			log.Fatal(err)
-		} else if err = w.Close(); err != nil {
-			log.Fatal(err)
+			return err
+		} else if err = os.WriteFile(files.json, data, 0644); err != nil {
+			return err
 		}
-		log.Printf("maze: created %s in %v\n", pngFile, time.Now().Sub(started))
+		log.Printf("maze: created %s in %v\n", files.json, time.Now().Sub(started))
 	}
 
-	if svgFile != "" {
-		started = time.Now()
-		w, err := os.OpenFile(svgFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Fatal(err)
-		} else if err = rg.RenderSVG(w, scale); err != nil {
-			log.Fatal(err)
-		} else if err = w.Close(); err != nil {
-			log.Fatal(err)
+	if files.png != "" {
+		if err := writeFile(files.png, func(w io.Writer) error { return rg.RenderPNG(w, scale) }); err != nil {
+			return err
 		}
-		log.Printf("maze: created %s in %v\n", svgFile, time.Now().Sub(started))
 	}
 
-	if pngSolvedFile != "" {
-		rg.Solve()
-		started = time.Now()
-		w, err := os.OpenFile(pngSolvedFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Fatal(err)
-		} else if err = rg.RenderPNG(w, scale); err != nil {
-			log.Fatal(err)
-		} else if err = w.Close(); err != nil {
-			log.Fatal(err)
+	if files.svg != "" {
+		if err := writeFile(files.svg, func(w io.Writer) error { return rg.RenderSVG(w, scale) }); err != nil {
+			return err
 		}
-		log.Printf("maze: created %s in %v\n", pngSolvedFile, time.Now().Sub(started))
 	}
 
-	if svgSolvedFile != "" {
-		started = time.Now()
-		w, err := os.OpenFile(svgSolvedFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Fatal(err)
-		} else if err = rg.RenderSVG(w, scale); err != nil {
-			log.Fatal(err)
-		} else if err = w.Close(); err != nil {
-			log.Fatal(err)
+	if files.pngSolved == "" && files.svgSolved == "" {
+		return nil
+	}
+	if err := solve(rg, solver); err != nil {
+		return err
+	}
+
+	if files.pngSolved != "" {
+		if err := writeFile(files.pngSolved, func(w io.Writer) error { return rg.RenderPNG(w, scale) }); err != nil {
+			return err
 		}
-		log.Printf("maze: created %s in %v\n", svgSolvedFile, time.Now().Sub(started))
 	}
 
+	if files.svgSolved != "" {
+		if err := writeFile(files.svgSolved, func(w io.Writer) error { return rg.RenderSVG(w, scale) }); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFile creates the named file and renders the maze into it.
+func writeFile(name string, render func(w io.Writer) error) error {
+	started := time.Now()
+	w, err := os.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	} else if err = render(w); err != nil {
+		_ = w.Close()
+		return err
+	} else if err = w.Close(); err != nil {
+		return err
+	}
+	log.Printf("maze: created %s in %v\n", name, time.Now().Sub(started))
+	return nil
+}
+
+// solve runs the named solver on the maze.
+func solve(rg *maze.Rectangle, solver string) error {
+	switch solver {
+	case "bfs":
+		return rg.SolveBFS()
+	case "astar":
+		return rg.SolveAStar()
+	case "dijkstra":
+		return rg.SolveDijkstra()
+	}
+	return rg.Solve()
 }
@@ -0,0 +1,78 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mdhender/maze"
+)
+
+func TestRender(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, solver := range []string{"dfs", "bfs", "astar", "dijkstra"} {
+		rg, err := maze.RectangleMazeWithSeed(8, 10, maze.WilsonGenerator{}, 1, false)
+		if err != nil {
+			t.Fatalf("%s: RectangleMazeWithSeed: %v", solver, err)
+		}
+		dir := t.TempDir()
+		files := outputs{
+			text:      filepath.Join(dir, "maze.txt"),
+			json:      filepath.Join(dir, "maze.json"),
+			png:       filepath.Join(dir, "maze.png"),
+			pngSolved: filepath.Join(dir, "maze-solved.png"),
+			svg:       filepath.Join(dir, "maze.svg"),
+			svgSolved: filepath.Join(dir, "maze-solved.svg"),
+		}
+		if err := render(rg, 10, solver, files); err != nil {
+			t.Fatalf("%s: render: %v", solver, err)
+		}
+		read := func(name string) []byte {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatalf("%s: %v", solver, err)
+			} else if len(data) == 0 {
+				t.Fatalf("%s: %s is empty", solver, name)
+			}
+			return data
+		}
+
+		// the unsolved images are written before solving, so only the solved images show the path
+		const pathStyle = "stroke:red"
+		if svg := read(files.svg); strings.Contains(string(svg), pathStyle) {
+			t.Errorf("%s: svg: unsolved image shows the path", solver)
+		}
+		if svg := read(files.svgSolved); !strings.Contains(string(svg), pathStyle) {
+			t.Errorf("%s: svg-solved: solved image doesn't show the path", solver)
+		}
+		if bytes.Equal(read(files.png), read(files.pngSolved)) {
+			t.Errorf("%s: png-solved: solved image matches the unsolved image", solver)
+		}
+		if _, err := maze.LoadJSON(read(files.json)); err != nil {
+			t.Errorf("%s: json: %v", solver, err)
+		}
+		read(files.text)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	for _, solver := range []string{"dfs", "bfs", "astar", "dijkstra"} {
+		rg, err := maze.RectangleMazeWithSeed(4, 4, maze.WilsonGenerator{}, 1, false)
+		if err != nil {
+			t.Fatalf("%s: RectangleMazeWithSeed: %v", solver, err)
+		}
+		if err := solve(rg, solver); err != nil {
+			t.Errorf("%s: solve: %v", solver, err)
+		} else if rg.SolutionPath() == nil {
+			t.Errorf("%s: solve: maze was not solved", solver)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mdhender/maze"
+)
+
+func TestGeneratedMazeIsFullyReachable(t *testing.T) {
+	for _, seed := range []int64{1, 2, 3, 42} {
+		grid, exits := maze.GenerateIntGrid(12, 18, seed)
+		if grid == nil {
+			t.Fatalf("seed %d: GenerateIntGrid returned nil", seed)
+		}
+		for _, exit := range exits {
+			if grid[exit[0]][exit[1]] != 0 {
+				t.Errorf("seed %d: exit %v is a wall", seed, exit)
+			}
+		}
+		if !isMazeFullyReachable(grid, exits) {
+			t.Errorf("seed %d: isMazeFullyReachable: want true, got false", seed)
+		}
+	}
+}
+
+func TestIsMazeFullyReachable(t *testing.T) {
+	// the open cell in the middle is walled off from the exits
+	grid := [][]int{
+		{0, 1, 1},
+		{1, 0, 1},
+		{1, 1, 0},
+	}
+	if isMazeFullyReachable(grid, [][2]int{{0, 0}, {2, 2}}) {
+		t.Errorf("isMazeFullyReachable: want false, got true")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// RectangleDecoy creates a maze that can't be solved, for testing solvers.
+// it carves a perfect maze and then closes one passage on the path from the entrance to the exit,
+// which cuts the maze into two regions. it returns the maze and the coordinates of every cell in
+// the region that can't be reached from the entrance, in row-major order. the exit is always in that region.
+func RectangleDecoy(height, width int) (*Rectangle, [][2]int, error) {
+	r, err := RectangleMazeWith(height, width, WilsonGenerator{}, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	g := r.g
+
+	// find the path to the exit and close one of its passages.
+	// carving leaves the walk pointers set, so clear them first.
+	g.clearSolution()
+	exit, _ := g.solveShortest(r.entrances)
+	var path []*cell
+	for c := exit; c != nil; c = c.to {
+		path = append(path, c)
+	}
+	// the passage is picked with a source seeded from the maze, so the seed still reproduces the decoy
+	n := rand.New(rand.NewSource(r.seed)).Intn(len(path) - 1)
+	path[n].unlinkFrom(path[n+1])
+	r.ResetSolution()
+
+	var unreachable [][2]int
+	for row, distances := range g.distancesFrom(r.entrances[0]) {
+		for col, distance := range distances {
+			if distance == -1 {
+				unreachable = append(unreachable, [2]int{row, col})
+			}
+		}
+	}
+	return r, unreachable, nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+func TestRectangleDecoy(t *testing.T) {
+	for n := 0; n < 5; n++ {
+		r, region, err := RectangleDecoy(8, 10)
+		if err != nil {
+			t.Fatalf("RectangleDecoy: %v", err)
+		}
+		if len(region) == 0 || len(region) == 8*10 {
+			t.Fatalf("RectangleDecoy: want part of the maze cut off, got %d cells", len(region))
+		}
+
+		// the region is exactly the cells that can't be reached from the entrance, and it holds the exit
+		unreachable := map[[2]int]bool{}
+		for _, rc := range region {
+			unreachable[rc] = true
+		}
+		for row, distances := range r.DistanceField() {
+			for col, distance := range distances {
+				if (distance == -1) != unreachable[[2]int{row, col}] {
+					t.Errorf("RectangleDecoy: cell (%d, %d): distance %d, in region %v", row, col, distance, unreachable[[2]int{row, col}])
+				}
+			}
+		}
+		if row, col := r.Exit(); !unreachable[[2]int{row, col}] {
+			t.Errorf("RectangleDecoy: want the exit in the region")
+		}
+
+		if err := r.Solve(); err == nil {
+			t.Errorf("Solve: want error, got nil")
+		}
+		if r.IsPerfect() {
+			t.Errorf("IsPerfect: want false, got true")
+		}
+	}
+	if _, _, err := RectangleDecoy(1, 10); err == nil {
+		t.Errorf("RectangleDecoy: 1 x 10: want error, got nil")
+	}
+}
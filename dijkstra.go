@@ -0,0 +1,76 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// SetCellWeight sets the cost of stepping into the cell when the maze is solved with SolveDijkstra.
+// every cell starts with a weight of 1. any existing solution is cleared.
+// it returns an error if the cell is out of bounds or the weight is negative.
+func (r *Rectangle) SetCellWeight(row, col, w int) error {
+	c, ok := r.g.at(row, col)
+	if !ok {
+		return fmt.Errorf("cell (%d, %d) is out of bounds", row, col)
+	} else if w < 0 {
+		return fmt.Errorf("cell (%d, %d): weight %d is negative", row, col, w)
+	}
+	c.weight = w
+	r.ResetSolution()
+	return nil
+}
+
+// SolveDijkstra finds the path from the entrance to the exit with the lowest total weight using Dijkstra's algorithm.
+// the cost of a path is the sum of the weights of the cells that it steps into.
+// when every cell has the same weight, this is the shortest path.
+// like SolveAStar, it always replaces any existing solution, and it returns an error if there is no path.
+func (r *Rectangle) SolveDijkstra() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ResetSolution()
+
+	// cost is the lowest known cost of reaching each cell from an entrance
+	cost := map[*cell]int{}
+	open := &astarQueue{}
+	for _, entrance := range r.entrances {
+		cost[entrance] = 0
+		heap.Push(open, &astarItem{c: entrance, seq: open.pushed})
+	}
+	var exit *cell
+	for open.Len() != 0 {
+		current := heap.Pop(open).(*astarItem).c
+		if current.visited {
+			// a cheaper path to this cell has already been expanded
+			continue
+		}
+		current.visited = true
+		if current.isExit() {
+			exit = current
+			break
+		}
+
+		for _, neighbor := range current.openNeighbors() {
+			if neighbor.visited {
+				continue
+			}
+			if known, ok := cost[neighbor]; ok && known <= cost[current]+neighbor.weight {
+				continue
+			}
+			cost[neighbor] = cost[current] + neighbor.weight
+			neighbor.to = current
+			heap.Push(open, &astarItem{c: neighbor, estimate: cost[neighbor], seq: open.pushed})
+		}
+	}
+
+	if exit == nil {
+		return fmt.Errorf("solve: no path from an entrance to an exit")
+	}
+
+	// flag each cell that is on the path between the entrance and the exit
+	r.g.markPath(exit)
+
+	r.solved = true
+	return nil
+}
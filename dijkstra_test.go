@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSolveDijkstraWeights(t *testing.T) {
+	r := loopMaze(t)
+	short := [][2]int{{0, 0}, {0, 1}, {0, 2}}
+	long := [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {1, 2}, {0, 2}}
+
+	// with uniform weights, the lowest cost is the shortest path
+	if err := r.SolveDijkstra(); err != nil {
+		t.Fatalf("SolveDijkstra: %v", err)
+	}
+	if got := r.SolutionPath(); !reflect.DeepEqual(got, short) {
+		t.Errorf("SolveDijkstra: uniform: want %v, got %v", short, got)
+	}
+
+	// a heavy cell on the short way costs more than the six steps of the long way
+	if err := r.SetCellWeight(0, 1, 10); err != nil {
+		t.Fatalf("SetCellWeight: %v", err)
+	}
+	if r.SolutionPath() != nil {
+		t.Errorf("SetCellWeight: want the solution cleared")
+	}
+	if err := r.SolveDijkstra(); err != nil {
+		t.Fatalf("SolveDijkstra: %v", err)
+	}
+	if got := r.SolutionPath(); !reflect.DeepEqual(got, long) {
+		t.Errorf("SolveDijkstra: heavy: want %v, got %v", long, got)
+	}
+	// the weights don't change the shortest path
+	if err := r.SolveBFS(); err != nil {
+		t.Fatalf("SolveBFS: %v", err)
+	}
+	if got := r.SolutionPath(); !reflect.DeepEqual(got, short) {
+		t.Errorf("SolveBFS: want %v, got %v", short, got)
+	}
+
+	// a cheaper detour is still longer, so the short way wins again
+	if err := r.SetCellWeight(0, 1, 4); err != nil {
+		t.Fatalf("SetCellWeight: %v", err)
+	}
+	if err := r.SolveDijkstra(); err != nil {
+		t.Fatalf("SolveDijkstra: %v", err)
+	}
+	if got := r.SolutionPath(); !reflect.DeepEqual(got, short) {
+		t.Errorf("SolveDijkstra: light: want %v, got %v", short, got)
+	}
+
+	if err := r.SetCellWeight(3, 0, 1); err == nil {
+		t.Errorf("SetCellWeight: out of bounds: want error, got nil")
+	}
+	if err := r.SetCellWeight(0, 0, -1); err == nil {
+		t.Errorf("SetCellWeight: negative: want error, got nil")
+	}
+}
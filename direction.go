@@ -0,0 +1,70 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "fmt"
+
+// Direction is one of the four sides of a cell.
+type Direction int
+
+const (
+	North Direction = iota
+	East
+	South
+	West
+)
+
+// searchOrder is the order that the solvers look at the neighbors of a cell.
+// it is fixed so that solving the same maze always explores the cells in the same order and finds
+// the same path, even when there are several shortest paths. solvers must walk neighbors in this
+// order (openNeighbors does) rather than ranging over a map, whose order is random.
+var searchOrder = [4]Direction{North, East, South, West}
+
+// opposite returns the direction on the other side of a cell.
+func (d Direction) opposite() Direction {
+	return (d + 2) % 4
+}
+
+// String implements the Stringer interface.
+func (d Direction) String() string {
+	switch d {
+	case North:
+		return "north"
+	case East:
+		return "east"
+	case South:
+		return "south"
+	case West:
+		return "west"
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// DirectionWeights biases the random walks used to carve a maze.
+// a walk picks each neighbor with a chance proportional to the weight of its direction,
+// so heavier East and West weights give the maze a horizontal grain.
+// negative weights are treated as zero. the zero value picks every neighbor with the same chance.
+type DirectionWeights struct {
+	North, East, South, West float64
+}
+
+// isUniform returns true if every direction has the same weight.
+func (w DirectionWeights) isUniform() bool {
+	return w.North == w.East && w.East == w.South && w.South == w.West
+}
+
+// of returns the weight for the direction.
+func (w DirectionWeights) of(dir Direction) float64 {
+	var weight float64
+	switch dir {
+	case North:
+		weight = w.North
+	case East:
+		weight = w.East
+	case South:
+		weight = w.South
+	case West:
+		weight = w.West
+	}
+	return max(weight, 0)
+}
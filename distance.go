@@ -0,0 +1,115 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "fmt"
+
+// DistanceField returns the number of steps from the entrance to every cell in the maze.
+// cells that can't be reached from the entrance are set to -1.
+// if the maze has more than one entrance, the distances are measured from the first one.
+func (r *Rectangle) DistanceField() [][]int {
+	return r.DistancesFrom(r.entrances[0].row, r.entrances[0].col)
+}
+
+// DistancesFrom returns the number of steps from the given cell to every cell in the maze.
+// cells that can't be reached are set to -1. it returns nil if the cell is out of bounds.
+func (r *Rectangle) DistancesFrom(row, col int) [][]int {
+	start, ok := r.g.at(row, col)
+	if !ok {
+		return nil
+	}
+	return r.g.distancesFrom(start)
+}
+
+// distancesFrom runs a breadth-first search over open passages, starting with the given cell.
+// it returns the number of steps from the start to every cell, using -1 for unreachable cells.
+func (g *grid) distancesFrom(start *cell) [][]int {
+	distances := make([][]int, g.height)
+	for row := 0; row < g.height; row++ {
+		distances[row] = make([]int, g.width)
+		for col := 0; col < g.width; col++ {
+			distances[row][col] = -1
+		}
+	}
+
+	distances[start.row][start.col] = 0
+	queue := []*cell{start}
+	for len(queue) != 0 {
+		// pop the first cell from the queue
+		current := queue[0]
+		queue = queue[1:]
+
+		// push all open neighbors that we haven't measured yet
+		for _, neighbor := range current.openNeighbors() {
+			if distances[neighbor.row][neighbor.col] == -1 {
+				distances[neighbor.row][neighbor.col] = distances[current.row][current.col] + 1
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return distances
+}
+
+// ReachableWithin returns the cells that can be reached from the given cell in n or fewer steps
+// through open passages. the cells are returned in breadth-first order, starting with the given cell.
+// it returns an error if the cell is out of bounds.
+func (r *Rectangle) ReachableWithin(row, col, n int) ([]CellInfo, error) {
+	start, ok := r.g.at(row, col)
+	if !ok {
+		return nil, fmt.Errorf("cell (%d, %d) is out of bounds", row, col)
+	} else if n < 0 {
+		return nil, nil
+	}
+
+	distance := map[*cell]int{start: 0}
+	reachable := []CellInfo{{Row: start.row, Col: start.col}}
+	queue := []*cell{start}
+	for len(queue) != 0 {
+		// pop the first cell from the queue
+		current := queue[0]
+		queue = queue[1:]
+
+		// stop expanding once we've reached the limit
+		if distance[current] == n {
+			continue
+		}
+
+		// push all open neighbors that we haven't seen yet
+		for _, neighbor := range current.openNeighbors() {
+			if _, ok := distance[neighbor]; !ok {
+				distance[neighbor] = distance[current] + 1
+				reachable = append(reachable, CellInfo{Row: neighbor.row, Col: neighbor.col})
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return reachable, nil
+}
+
+// LongestPath returns the two cells that are farthest apart in the maze and the number of steps between them.
+// it uses two breadth-first searches: the first finds the cell farthest from the entrance
+// and the second finds the cell farthest from that one. the result is exact for perfect mazes
+// and an estimate for mazes with loops.
+func (r *Rectangle) LongestPath() (from, to [2]int, length int) {
+	start := r.g.farthestFrom(r.entrances[0])
+	end := r.g.farthestFrom(start)
+	distances := r.g.distancesFrom(start)
+	return [2]int{start.row, start.col}, [2]int{end.row, end.col}, distances[end.row][end.col]
+}
+
+// farthestFrom returns the reachable cell with the most steps from the start.
+// ties go to the first cell in row-major order.
+func (g *grid) farthestFrom(start *cell) *cell {
+	distances := g.distancesFrom(start)
+	farthest := start
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			if distances[row][col] > distances[farthest.row][farthest.col] {
+				farthest = g.cells[row][col]
+			}
+		}
+	}
+	return farthest
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistanceField(t *testing.T) {
+	r := loopMaze(t)
+	want := [][]int{
+		{0, 1, 2},
+		{1, 2, 3},
+		{2, 3, 4},
+	}
+	if got := r.DistanceField(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DistanceField: want %v, got %v", want, got)
+	}
+
+	// measured from the exit instead
+	want = [][]int{
+		{2, 1, 0},
+		{3, 4, 1},
+		{4, 3, 2},
+	}
+	if got := r.DistancesFrom(0, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("DistancesFrom: want %v, got %v", want, got)
+	}
+	if got := r.DistancesFrom(3, 0); got != nil {
+		t.Errorf("DistancesFrom: out of bounds: want nil, got %v", got)
+	}
+}
+
+func TestDistanceFieldUnreachable(t *testing.T) {
+	// the exit is walled off from the rest of the maze
+	r := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 1},
+		passage{{0, 0}, {0, 1}}, passage{{0, 0}, {1, 0}},
+	)
+	want := [][]int{
+		{0, 1},
+		{1, -1},
+	}
+	if got := r.DistanceField(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DistanceField: want %v, got %v", want, got)
+	}
+}
+
+func TestLongestPath(t *testing.T) {
+	// a serpentine is one long corridor, so its ends are the farthest apart
+	r := RectangleFromGrid(serpentine(t, 3, 4), false)
+	from, to, length := r.LongestPath()
+	if length != 11 {
+		t.Errorf("LongestPath: serpentine: want 11, got %d", length)
+	}
+	ends := map[[2]int]bool{{0, 0}: true, {2, 3}: true}
+	if !ends[from] || !ends[to] || from == to {
+		t.Errorf("LongestPath: serpentine: want (0, 0) and (2, 3), got %v and %v", from, to)
+	}
+
+	// in a perfect maze, no two cells are farther apart than the longest path
+	r, err := RectangleMazeWith(10, 14, WilsonGenerator{}, false, WithSeed(4))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	from, to, length = r.LongestPath()
+	if got := r.DistancesFrom(from[0], from[1])[to[0]][to[1]]; got != length {
+		t.Errorf("LongestPath: %v to %v: want %d steps, got %d", from, to, length, got)
+	}
+	for row := 0; row < 10; row++ {
+		for col := 0; col < 14; col++ {
+			for _, steps := range r.DistancesFrom(row, col) {
+				for _, n := range steps {
+					if n > length {
+						t.Fatalf("LongestPath: (%d, %d) has a cell %d steps away, more than %d", row, col, n, length)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDistancesFrom(t *testing.T) {
+	// the serpentine is one corridor, so the distance between two cells is how far apart they are along it
+	r := RectangleFromGrid(serpentine(t, 3, 4), false)
+	along := func(row, col int) int {
+		if row%2 == 1 {
+			col = 3 - col
+		}
+		return row*4 + col
+	}
+	for _, from := range [][2]int{{0, 0}, {1, 2}, {2, 3}} {
+		got := r.DistancesFrom(from[0], from[1])
+		for row := 0; row < 3; row++ {
+			for col := 0; col < 4; col++ {
+				want := along(row, col) - along(from[0], from[1])
+				if want < 0 {
+					want = -want
+				}
+				if got[row][col] != want {
+					t.Errorf("DistancesFrom(%d, %d): (%d, %d): want %d, got %d", from[0], from[1], row, col, want, got[row][col])
+				}
+			}
+		}
+	}
+
+	// in any maze, the distance from a to b is the distance from b to a
+	r, err := RectangleMazeWith(7, 9, WilsonGenerator{}, false, WithSeed(2))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	a, b := r.DistancesFrom(1, 7), r.DistancesFrom(5, 2)
+	if a[5][2] != b[1][7] || a[5][2] <= 0 {
+		t.Errorf("DistancesFrom: (1, 7) to (5, 2) is %d, (5, 2) to (1, 7) is %d", a[5][2], b[1][7])
+	}
+	if a[1][7] != 0 || b[5][2] != 0 {
+		t.Errorf("DistancesFrom: want 0 at the start, got %d and %d", a[1][7], b[5][2])
+	}
+	// no cell is farther from one start than from the other start plus the distance between them
+	for row := 0; row < 7; row++ {
+		for col := 0; col < 9; col++ {
+			if d := a[row][col] - b[row][col]; d > a[5][2] || -d > a[5][2] {
+				t.Errorf("DistancesFrom: (%d, %d): %d and %d break the triangle inequality", row, col, a[row][col], b[row][col])
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// RectangleRecursiveDivision creates a maze using the recursive division algorithm.
+// instead of carving passages, it starts with an open grid and adds walls, so the maze has
+// long straight walls that look more like the floor plan of a building.
+func RectangleRecursiveDivision(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, RecursiveDivisionGenerator{}, solve)
+}
+
+// carveRecursiveDivision removes every internal wall and then divides the grid into chambers.
+func (g *grid) carveRecursiveDivision(rng *rand.Rand) {
+	for _, c := range g.allCells() {
+		if c.neighbors.east != nil {
+			c.linkTo(c.neighbors.east)
+		}
+		if c.neighbors.south != nil {
+			c.linkTo(c.neighbors.south)
+		}
+		c.in = true
+	}
+	g.divide(0, 0, g.height, g.width, rng)
+}
+
+// divide splits the chamber that starts at row, col with a wall that has a single gap in it,
+// then divides the two halves. chambers that are one cell tall or wide are left as corridors.
+// a chamber is split across its longer side, or at random if it is square.
+func (g *grid) divide(row, col, height, width int, rng *rand.Rand) {
+	if height < 2 || width < 2 {
+		return
+	}
+
+	horizontal := height > width || (height == width && rng.Intn(2) == 0)
+	if horizontal {
+		// add a wall along the southern side of a random row, leaving a gap at a random column
+		split, gap := row+rng.Intn(height-1), col+rng.Intn(width)
+		for c := col; c < col+width; c++ {
+			if c != gap {
+				g.cells[split][c].walls.south = true
+				g.cells[split+1][c].walls.north = true
+			}
+		}
+		g.divide(row, col, split-row+1, width, rng)
+		g.divide(split+1, col, row+height-split-1, width, rng)
+		return
+	}
+
+	// add a wall along the eastern side of a random column, leaving a gap at a random row
+	split, gap := col+rng.Intn(width-1), row+rng.Intn(height)
+	for r := row; r < row+height; r++ {
+		if r != gap {
+			g.cells[r][split].walls.east = true
+			g.cells[r][split+1].walls.west = true
+		}
+	}
+	g.divide(row, col, height, split-col+1, rng)
+	g.divide(row, split+1, height, col+width-split-1, rng)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// RenderDOT writes the maze as an undirected Graphviz graph, with a node for every cell and
+// an edge for every open passage. nodes are named for their row and column, like "r0c1".
+// the entrance is drawn in green and the exit in blue. masked cells are left out.
+func (r *Rectangle) RenderDOT(w io.Writer) error {
+	return r.g.toDOT(w)
+}
+
+// toDOT writes the grid as a Graphviz graph.
+func (g *grid) toDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "graph maze {\n")
+	fmt.Fprintf(bw, "\tnode [shape=circle];\n")
+
+	// write the nodes, adding attributes for the gates and the solution path
+	for _, c := range g.allCells() {
+		if c.masked {
+			continue
+		}
+		attrs := fmt.Sprintf("label=\"%d,%d\"", c.row, c.col)
+		if c.entrance {
+			attrs += ", color=green, style=bold"
+		} else if c.exit {
+			attrs += ", color=blue, style=bold"
+		} else if c.onPath {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(bw, "\tr%dc%d [%s];\n", c.row, c.col, attrs)
+	}
+
+	// write the edges. every passage is seen from both ends, so only write it from the first cell.
+	for _, c := range g.allCells() {
+		for _, neighbor := range c.openNeighbors() {
+			if neighbor.row > c.row || (neighbor.row == c.row && neighbor.col > c.col) {
+				fmt.Fprintf(bw, "\tr%dc%d -- r%dc%d;\n", c.row, c.col, neighbor.row, neighbor.col)
+			}
+		}
+	}
+
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
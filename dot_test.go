@@ -0,0 +1,49 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRenderDOT(t *testing.T) {
+	r, err := RectangleMazeWith(7, 9, WilsonGenerator{}, true, WithSeed(8))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	var b bytes.Buffer
+	if err := r.RenderDOT(&b); err != nil {
+		t.Fatalf("RenderDOT: %v", err)
+	}
+	dot := b.String()
+	if !strings.HasPrefix(dot, "graph maze {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Fatalf("RenderDOT: want a graph, got\n%s", dot)
+	}
+
+	// a perfect maze has one node per cell and one fewer edges than nodes
+	nodes := regexp.MustCompile(`(?m)^\tr\d+c\d+ \[`).FindAllString(dot, -1)
+	edges := regexp.MustCompile(`(?m)^\tr(\d+)c(\d+) -- r(\d+)c(\d+);$`).FindAllStringSubmatch(dot, -1)
+	if len(nodes) != 7*9 {
+		t.Errorf("RenderDOT: nodes: want %d, got %d", 7*9, len(nodes))
+	}
+	if len(edges) != 7*9-1 {
+		t.Errorf("RenderDOT: edges: want %d, got %d", 7*9-1, len(edges))
+	}
+
+	// the gates are drawn differently from the other cells
+	row, col := r.Entrance()
+	if entrance := regexp.MustCompile(`r` + strconv.Itoa(row) + `c` + strconv.Itoa(col) + ` \[[^]]*color=green`); !entrance.MatchString(dot) {
+		t.Errorf("RenderDOT: want the entrance colored green")
+	}
+	row, col = r.Exit()
+	if exit := regexp.MustCompile(`r` + strconv.Itoa(row) + `c` + strconv.Itoa(col) + ` \[[^]]*color=blue`); !exit.MatchString(dot) {
+		t.Errorf("RenderDOT: want the exit colored blue")
+	}
+	if got := strings.Count(dot, "color=red"); got != len(r.SolutionPath())-2 {
+		t.Errorf("RenderDOT: want %d path nodes, got %d", len(r.SolutionPath())-2, got)
+	}
+}
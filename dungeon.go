@@ -0,0 +1,158 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// room is a rectangular chamber with no internal walls.
+type room struct {
+	row, col      int
+	height, width int
+}
+
+// overlaps returns true if the rooms overlap or touch each other.
+func (r room) overlaps(other room) bool {
+	return r.row <= other.row+other.height && other.row <= r.row+r.height &&
+		r.col <= other.col+other.width && other.col <= r.col+r.width
+}
+
+// GenerateDungeon creates a maze with roomCount non-overlapping rooms of random sizes.
+// the space between the rooms is carved using Wilson's algorithm and every room is
+// connected to the corridors by at least one doorway.
+// it returns an error if the rooms can't be placed in the grid.
+func GenerateDungeon(height, width, roomCount int, rng *rand.Rand) (*Rectangle, error) {
+	if err := validateDimensions(height, width); err != nil {
+		return nil, err
+	} else if roomCount < 0 {
+		return nil, fmt.Errorf("dungeon: room count must not be negative")
+	}
+	g := createGrid(height, width)
+
+	// the gates are placed with their own source so that they don't depend on the rooms or corridors
+	gates := gateSource(rng)
+
+	// rooms are at least 2x2 and at most a quarter of the smaller dimension of the grid
+	maxSize := max(2, min(height, width)/4)
+
+	// place the rooms at random, retrying when a room overlaps a room that has already been placed
+	var rooms []room
+	for attempts := 0; len(rooms) < roomCount && attempts < roomCount*100; attempts++ {
+		rm := room{height: 2 + rng.Intn(maxSize-1), width: 2 + rng.Intn(maxSize-1)}
+		rm.row, rm.col = rng.Intn(height-rm.height+1), rng.Intn(width-rm.width+1)
+		placed := true
+		for _, other := range rooms {
+			if rm.overlaps(other) {
+				placed = false
+				break
+			}
+		}
+		if placed {
+			rooms = append(rooms, rm)
+		}
+	}
+	if len(rooms) < roomCount {
+		return nil, fmt.Errorf("dungeon: placed only %d of %d rooms", len(rooms), roomCount)
+	}
+
+	// open the rooms and add them to the maze so that the random walks stop when they reach one.
+	for _, rm := range rooms {
+		g.addRoom(rm.row, rm.col, rm.height, rm.width)
+	}
+
+	// carve the corridors. every walk ends in a room or a corridor that is connected to a room.
+	g.carveWilson(rng)
+
+	// a room that no walk reached is still isolated, so connect everything
+	g.ensureConnected(rng)
+
+	entrance, exit := placeGates(g, gates)
+
+	return &Rectangle{
+		g:         g,
+		entrances: []*cell{entrance},
+		exits:     []*cell{exit},
+		algorithm: "dungeon",
+	}, nil
+}
+
+// CarveRoom opens an empty room in the maze by removing every wall between the cells in the rectangle
+// that starts at row, col and is h cells tall and w cells wide. the walls around the outside of the room
+// are left alone. it only opens walls, so a connected maze stays connected. any existing solution is cleared.
+// it returns an error if the room is empty, isn't inside the maze, or includes masked cells.
+func (r *Rectangle) CarveRoom(row, col, h, w int) error {
+	if h < 1 || w < 1 {
+		return fmt.Errorf("room: invalid size %d x %d", h, w)
+	} else if !r.g.inBounds(row, col) || !r.g.inBounds(row+h-1, col+w-1) {
+		return fmt.Errorf("room: cells (%d, %d) to (%d, %d) are out of bounds", row, col, row+h-1, col+w-1)
+	}
+	for y := row; y < row+h; y++ {
+		for x := col; x < col+w; x++ {
+			if r.g.cells[y][x].masked {
+				return fmt.Errorf("room: cell (%d, %d) is masked", y, x)
+			}
+		}
+	}
+	r.g.addRoom(row, col, h, w)
+	r.ResetSolution()
+	return nil
+}
+
+// addRoom removes all the walls between the cells in the rectangle and marks them as in the maze.
+// the walls on the outside of the rectangle are not changed.
+func (g *grid) addRoom(row, col, height, width int) {
+	for r := row; r < row+height; r++ {
+		for c := col; c < col+width; c++ {
+			cell := g.cells[r][c]
+			if r < row+height-1 {
+				cell.linkTo(cell.neighbors.south)
+			}
+			if c < col+width-1 {
+				cell.linkTo(cell.neighbors.east)
+			}
+			cell.in = true
+		}
+	}
+}
+
+// ensureConnected opens walls at random until every cell in the grid can be reached from every other cell.
+func (g *grid) ensureConnected(rng *rand.Rand) {
+	// reached tracks the cells that are connected to the first cell
+	reached := make(map[*cell]bool)
+	flood := func(start *cell) {
+		reached[start] = true
+		queue := []*cell{start}
+		for len(queue) != 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range current.openNeighbors() {
+				if !reached[neighbor] {
+					reached[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+	}
+
+	flood(g.cells[0][0])
+	for len(reached) < g.height*g.width {
+		// find all the walls between a connected cell and a disconnected cell
+		var candidates [][2]*cell
+		for _, c := range g.allCells() {
+			if !reached[c] {
+				continue
+			}
+			for _, neighbor := range c.neighborhood {
+				if !reached[neighbor] {
+					candidates = append(candidates, [2]*cell{c, neighbor})
+				}
+			}
+		}
+		// open one of them at random and connect everything behind it
+		pair := candidates[rng.Intn(len(candidates))]
+		pair[0].linkTo(pair[1])
+		flood(pair[1])
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCarveRoom(t *testing.T) {
+	r, err := RectangleMazeWith(10, 10, WilsonGenerator{}, true, WithSeed(3))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	const row, col, h, w = 2, 3, 3, 4
+	inside := func(y, x int) bool { return y >= row && y < row+h && x >= col && x < col+w }
+
+	// a room of 3 x 4 cells has 3 x 3 walls that run north-south and 2 x 4 that run east-west
+	opened := 0
+	for y := row; y < row+h; y++ {
+		for x := col; x < col+w; x++ {
+			c := r.g.cells[y][x]
+			if x+1 < col+w && !c.eastIsOpen() {
+				opened++
+			}
+			if y+1 < row+h && !c.southIsOpen() {
+				opened++
+			}
+		}
+	}
+	if opened == 0 {
+		t.Fatalf("CarveRoom: want closed walls in the room before carving it")
+	}
+	horizontal, vertical := passages(r)
+	before := r.OpeningsMask()
+
+	if err := r.CarveRoom(row, col, h, w); err != nil {
+		t.Fatalf("CarveRoom: %v", err)
+	}
+	if h2, v2 := passages(r); h2+v2 != horizontal+vertical+opened {
+		t.Errorf("CarveRoom: passages: want %d, got %d", horizontal+vertical+opened, h2+v2)
+	}
+	// every wall inside the room is open and every wall outside of it is unchanged
+	after := r.OpeningsMask()
+	for y := range after {
+		for x := range after[y] {
+			if !inside(y, x) {
+				if after[y][x] != before[y][x] {
+					t.Errorf("CarveRoom: (%d, %d): want %04b, got %04b", y, x, before[y][x], after[y][x])
+				}
+				continue
+			}
+			c := r.g.cells[y][x]
+			for _, dir := range []Direction{North, East, South, West} {
+				n := c.neighbor(dir)
+				if n != nil && inside(n.row, n.col) && c.wall(dir) {
+					t.Errorf("CarveRoom: (%d, %d): want the wall to the %v open", y, x, dir)
+				} else if (n == nil || !inside(n.row, n.col)) && c.wall(dir) != (before[y][x]&(1<<dir) == 0) {
+					t.Errorf("CarveRoom: (%d, %d): want the outer wall to the %v unchanged", y, x, dir)
+				}
+			}
+		}
+	}
+	if r.IsPerfect() {
+		t.Errorf("CarveRoom: IsPerfect: want false, got true")
+	}
+	if r.SolutionPath() != nil {
+		t.Errorf("CarveRoom: want the solution cleared")
+	}
+
+	r.g.cells[8][8].masked = true
+	for _, tc := range []struct {
+		name           string
+		row, col, h, w int
+	}{
+		{"empty", 0, 0, 0, 3},
+		{"negative", -1, 0, 2, 2},
+		{"too tall", 8, 0, 3, 2},
+		{"too wide", 0, 8, 2, 3},
+		{"masked", 7, 7, 2, 2},
+	} {
+		if err := r.CarveRoom(tc.row, tc.col, tc.h, tc.w); err == nil {
+			t.Errorf("CarveRoom: %s: want error, got nil", tc.name)
+		}
+	}
+}
+
+func TestGenerateDungeon(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		r, err := GenerateDungeon(20, 24, 5, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatalf("seed %d: GenerateDungeon: %v", seed, err)
+		}
+		// a room has no walls inside it, so each one has at least one 2 x 2 block of open cells.
+		// the corridors are carved as a tree, which can't have one.
+		open := map[[2]int]bool{}
+		for _, c := range r.g.allCells() {
+			e, s := c.neighbors.east, c.neighbors.south
+			if e != nil && s != nil && c.eastIsOpen() && c.southIsOpen() && e.southIsOpen() && s.eastIsOpen() {
+				for _, rc := range [][2]int{{c.row, c.col}, {e.row, e.col}, {s.row, s.col}, {c.row + 1, c.col + 1}} {
+					open[rc] = true
+				}
+			}
+		}
+		if len(open) < 5*4 {
+			t.Errorf("seed %d: want 5 rooms, got only %d cells in rooms", seed, len(open))
+		}
+		// every cell, in a room or not, can be reached from the entrance
+		distances := r.DistanceField()
+		for rc := range open {
+			if distances[rc[0]][rc[1]] < 0 {
+				t.Errorf("seed %d: room cell %v can't be reached from the entrance", seed, rc)
+			}
+		}
+		for row := range distances {
+			for col, n := range distances[row] {
+				if n < 0 {
+					t.Errorf("seed %d: (%d, %d) can't be reached from the entrance", seed, row, col)
+				}
+			}
+		}
+		if err := r.Solve(); err != nil {
+			t.Errorf("seed %d: Solve: %v", seed, err)
+		} else {
+			checkPath(t, r, r.SolutionPath())
+		}
+	}
+
+	// the rooms can't overlap or touch, so only a few fit in a small grid
+	if _, err := GenerateDungeon(6, 6, 20, rand.New(rand.NewSource(1))); err == nil {
+		t.Errorf("GenerateDungeon: too many rooms: want error, got nil")
+	}
+	if _, err := GenerateDungeon(6, 6, -1, rand.New(rand.NewSource(1))); err == nil {
+		t.Errorf("GenerateDungeon: negative room count: want error, got nil")
+	}
+}
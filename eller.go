@@ -0,0 +1,75 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// RectangleEller creates a maze using Eller's algorithm.
+// the algorithm only tracks set membership for the current row, so it could stream rows
+// to a writer; here the rows are still collected into a full grid for rendering.
+func RectangleEller(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, EllerGenerator{}, solve)
+}
+
+// carveEller carves passages through the grid one row at a time using Eller's algorithm.
+func (g *grid) carveEller(rng *rand.Rand) {
+	// sets holds the set id for each column of the current row. zero means no set yet.
+	sets := make([]int, g.width)
+	nextSet := 1
+
+	for row := 0; row < g.height; row++ {
+		cells := g.cells[row]
+		isLastRow := row == g.height-1
+
+		// put every cell that isn't in a set into a new set of its own
+		for col := range sets {
+			if sets[col] == 0 {
+				sets[col] = nextSet
+				nextSet++
+			}
+			cells[col].in = true
+		}
+
+		// randomly join adjacent cells that are in different sets.
+		// on the last row, every pair in different sets must be joined to connect the maze.
+		for col := 0; col < g.width-1; col++ {
+			if sets[col] == sets[col+1] || !(isLastRow || rng.Intn(2) == 0) {
+				continue
+			}
+			cells[col].linkTo(cells[col+1])
+			merged := sets[col+1]
+			for k := range sets {
+				if sets[k] == merged {
+					sets[k] = sets[col]
+				}
+			}
+		}
+		if isLastRow {
+			break
+		}
+
+		// group the columns by set, keeping the sets in the order they appear in the row
+		var order []int
+		members := make(map[int][]int)
+		for col, set := range sets {
+			if _, ok := members[set]; !ok {
+				order = append(order, set)
+			}
+			members[set] = append(members[set], col)
+		}
+
+		// every set must extend at least one cell down into the next row
+		next := make([]int, g.width)
+		for _, set := range order {
+			cols := members[set]
+			rng.Shuffle(len(cols), func(i, j int) {
+				cols[i], cols[j] = cols[j], cols[i]
+			})
+			for _, col := range cols[:1+rng.Intn(len(cols))] {
+				cells[col].linkTo(cells[col].neighbors.south)
+				next[col] = set
+			}
+		}
+		sets = next
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "fmt"
+
+// SetEntrance moves the entrance to the given cell, which must be on an outer edge of the maze.
+// the outer walls of the old entrances are closed and the outer wall of the new one is opened,
+// preferring the northern wall for corner cells. any existing solution is cleared.
+// it returns an error if the cell is masked or is an exit.
+func (r *Rectangle) SetEntrance(row, col int) error {
+	c, err := r.g.edgeCell(row, col)
+	if err != nil {
+		return fmt.Errorf("entrance: %w", err)
+	} else if c.isExit() {
+		return fmt.Errorf("entrance: cell (%d, %d) is an exit", row, col)
+	}
+	for _, old := range r.entrances {
+		old.entrance = false
+		r.g.closeGate(old)
+	}
+	c.entrance = true
+	c.openGate(North, West, South, East)
+	r.entrances = []*cell{c}
+	r.ResetSolution()
+	return nil
+}
+
+// SetExit moves the exit to the given cell, which must be on an outer edge of the maze.
+// the outer walls of the old exits are closed and the outer wall of the new one is opened,
+// preferring the southern wall for corner cells. any existing solution is cleared.
+// it returns an error if the cell is masked or is an entrance.
+func (r *Rectangle) SetExit(row, col int) error {
+	c, err := r.g.edgeCell(row, col)
+	if err != nil {
+		return fmt.Errorf("exit: %w", err)
+	} else if c.isEntrance() {
+		return fmt.Errorf("exit: cell (%d, %d) is an entrance", row, col)
+	}
+	for _, old := range r.exits {
+		old.exit = false
+		r.g.closeGate(old)
+	}
+	c.exit = true
+	c.openGate(South, East, North, West)
+	r.exits = []*cell{c}
+	r.ResetSolution()
+	return nil
+}
+
+// SetEntranceSpan replaces the entrances with length adjacent cells along the given edge of the maze,
+// starting with the cell at start. start is a column for the northern and southern edges and a row
+// for the eastern and western edges. the outer wall of every cell in the span is opened, making one
+// wide gate, and any existing solution is cleared.
+// it returns an error if the span doesn't fit on the edge.
+func (r *Rectangle) SetEntranceSpan(edge Direction, start, length int) error {
+	// collect the cells in the span
+	var span []*cell
+	for n := start; n < start+length; n++ {
+		var row, col int
+		switch edge {
+		case North:
+			row, col = 0, n
+		case East:
+			row, col = n, r.g.width-1
+		case South:
+			row, col = r.g.height-1, n
+		case West:
+			row, col = n, 0
+		default:
+			return fmt.Errorf("entrance: invalid edge %d", edge)
+		}
+		c, ok := r.g.at(row, col)
+		if !ok {
+			return fmt.Errorf("entrance: span %d to %d does not fit on the %s edge", start, start+length-1, edge)
+		}
+		if c.neighbor(edge) != nil {
+			return fmt.Errorf("entrance: cell (%d, %d) has no outer wall to the %s", row, col, edge)
+		}
+		span = append(span, c)
+	}
+	if len(span) == 0 {
+		return fmt.Errorf("entrance: span length %d must be at least 1", length)
+	}
+
+	// replace the old entrances with the span
+	for _, old := range r.entrances {
+		old.entrance = false
+		r.g.closeGate(old)
+	}
+	for _, c := range span {
+		c.entrance = true
+		c.openGate(edge)
+	}
+	r.entrances = span
+	r.ResetSolution()
+	return nil
+}
+
+// AddEntrance adds another entrance at the given cell, which must be on an outer edge of the maze.
+// the existing entrances are kept. the outer wall is opened like SetEntrance does,
+// and any existing solution is cleared. adding a cell that is already an entrance does nothing.
+// like SetEntrance, it returns an error if the cell is masked or is an exit.
+func (r *Rectangle) AddEntrance(row, col int) error {
+	c, err := r.g.edgeCell(row, col)
+	if err != nil {
+		return fmt.Errorf("entrance: %w", err)
+	} else if c.isExit() {
+		return fmt.Errorf("entrance: cell (%d, %d) is an exit", row, col)
+	} else if c.isEntrance() {
+		return nil
+	}
+	c.entrance = true
+	c.openGate(North, West, South, East)
+	r.entrances = append(r.entrances, c)
+	r.ResetSolution()
+	return nil
+}
+
+// AddExit adds another exit at the given cell, which must be on an outer edge of the maze.
+// the existing exits are kept. the outer wall is opened like SetExit does,
+// and any existing solution is cleared. adding a cell that is already an exit does nothing.
+// like SetExit, it returns an error if the cell is masked or is an entrance.
+func (r *Rectangle) AddExit(row, col int) error {
+	c, err := r.g.edgeCell(row, col)
+	if err != nil {
+		return fmt.Errorf("exit: %w", err)
+	} else if c.isEntrance() {
+		return fmt.Errorf("exit: cell (%d, %d) is an entrance", row, col)
+	} else if c.isExit() {
+		return nil
+	}
+	c.exit = true
+	c.openGate(South, East, North, West)
+	r.exits = append(r.exits, c)
+	r.ResetSolution()
+	return nil
+}
+
+// SealBorder closes every outer wall of the maze except at the entrances and exits, which are opened again
+// on the same side that they were opened on before. walls that face a masked cell count as outer walls.
+func (r *Rectangle) SealBorder() {
+	for _, c := range r.g.allCells() {
+		if !c.masked {
+			r.g.closeGate(c)
+		}
+	}
+}
+
+// edgeCell returns the cell at the given coordinates.
+// it returns an error if the cell is out of bounds, not on an outer edge of the grid, or masked.
+func (g *grid) edgeCell(row, col int) (*cell, error) {
+	c, ok := g.at(row, col)
+	if !ok {
+		return nil, fmt.Errorf("cell (%d, %d) is out of bounds", row, col)
+	} else if row != 0 && row != g.height-1 && col != 0 && col != g.width-1 {
+		return nil, fmt.Errorf("cell (%d, %d) is not on an outer edge", row, col)
+	} else if c.masked {
+		return nil, fmt.Errorf("cell (%d, %d) is masked", row, col)
+	}
+	return c, nil
+}
+
+// closeGate closes the outer walls of a cell that is no longer a gate.
+// if the cell is still a gate, the outer wall on its gate side is opened again.
+func (g *grid) closeGate(c *cell) {
+	for _, dir := range []Direction{North, East, South, West} {
+		if c.neighbor(dir) == nil {
+			c.setWall(dir, true)
+		}
+	}
+	if c.isEntrance() || c.isExit() {
+		c.openGate(c.gate)
+	}
+}
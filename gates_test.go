@@ -0,0 +1,237 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// outerOpening is an open outer wall.
+type outerOpening struct {
+	row, col int
+	side     Direction
+}
+
+// outerOpenings returns the open outer walls of the maze in row-major order.
+func outerOpenings(r *Rectangle) []outerOpening {
+	var openings []outerOpening
+	for _, c := range r.g.allCells() {
+		if c.masked {
+			continue
+		}
+		for _, dir := range searchOrder {
+			if c.neighbor(dir) == nil && !c.wall(dir) {
+				openings = append(openings, outerOpening{c.row, c.col, dir})
+			}
+		}
+	}
+	return openings
+}
+
+func TestSealBorder(t *testing.T) {
+	r, err := RectangleMaze(5, 5, false)
+	if err != nil {
+		t.Fatalf("RectangleMaze: %v", err)
+	}
+	// punch holes in the border, then seal it
+	for _, c := range r.g.allCells() {
+		for _, dir := range searchOrder {
+			if c.neighbor(dir) == nil {
+				c.setWall(dir, false)
+			}
+		}
+	}
+	r.SealBorder()
+
+	entranceRow, entranceCol := r.Entrance()
+	exitRow, exitCol := r.Exit()
+	want := []outerOpening{{entranceRow, entranceCol, North}, {exitRow, exitCol, South}}
+	got := outerOpenings(r)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("openings: want %v, got %v", want, got)
+	}
+	if err := r.Solve(); err != nil {
+		t.Errorf("Solve: %v", err)
+	}
+}
+
+func TestSealBorderKeepsGateSides(t *testing.T) {
+	r, err := RectangleMaze(4, 4, false)
+	if err != nil {
+		t.Fatalf("RectangleMaze: %v", err)
+	}
+	// open the entrance on the eastern wall of the north-east corner and the exit
+	// on the western wall of the south-west corner. both cells have another outer wall.
+	if err := r.SetEntranceSpan(East, 0, 1); err != nil {
+		t.Fatalf("SetEntranceSpan: %v", err)
+	}
+	if err := r.SetExit(3, 0); err != nil {
+		t.Fatalf("SetExit: %v", err)
+	}
+	exit := r.g.cells[3][0]
+	exit.setWall(South, true)
+	exit.openGate(West)
+
+	want := []outerOpening{{0, 3, East}, {3, 0, West}}
+	check := func(name string, r *Rectangle) {
+		t.Helper()
+		got := outerOpenings(r)
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("%s: openings: want %v, got %v", name, want, got)
+		}
+	}
+	check("before", r)
+	r.SealBorder()
+	check("SealBorder", r)
+
+	var b bytes.Buffer
+	if err := r.WriteBinary(&b); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	loaded, err := ReadBinary(&b)
+	if err != nil {
+		t.Fatalf("ReadBinary: %v", err)
+	}
+	check("ReadBinary", loaded)
+	if want, got := renderText(t, r), renderText(t, loaded); got != want {
+		t.Errorf("ReadBinary: RenderText: want\n%s\ngot\n%s", want, got)
+	}
+
+	loaded = roundTripJSON(t, r)
+	loaded.SealBorder()
+	check("LoadJSON", loaded)
+}
+
+func TestSetEntranceAndExit(t *testing.T) {
+	r := loopMaze(t)
+	if err := r.SetEntrance(2, 0); err != nil {
+		t.Fatalf("SetEntrance: %v", err)
+	} else if err := r.SetExit(2, 2); err != nil {
+		t.Fatalf("SetExit: %v", err)
+	}
+	want := []outerOpening{{2, 0, West}, {2, 2, South}}
+	if got := outerOpenings(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("SetEntrance and SetExit: openings: want %v, got %v", want, got)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		set      func(row, col int) error
+		row, col int
+	}{
+		{"SetEntrance: the exit", r.SetEntrance, 2, 2},
+		{"SetExit: the entrance", r.SetExit, 2, 0},
+		{"SetEntrance: out of bounds", r.SetEntrance, 3, 0},
+		{"SetExit: not on an edge", r.SetExit, 1, 1},
+	} {
+		if err := tc.set(tc.row, tc.col); err == nil {
+			t.Errorf("%s: want error, got nil", tc.name)
+		}
+	}
+	// the failed calls left the gates alone
+	if got := outerOpenings(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("failed calls: openings: want %v, got %v", want, got)
+	}
+	if err := r.Solve(); err != nil {
+		t.Errorf("Solve: %v", err)
+	}
+
+	// the corners of the mask are off, so the corner cells aren't part of the maze
+	mask := [][]bool{
+		{false, true, true, false},
+		{true, true, true, true},
+		{false, true, true, false},
+	}
+	masked, err := RectangleMazeWith(3, 4, WilsonGenerator{}, false, withMask(mask), WithSeed(1))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	if err := masked.SetEntrance(0, 0); err == nil {
+		t.Errorf("SetEntrance: masked cell: want error, got nil")
+	}
+	if err := masked.SetExit(2, 3); err == nil {
+		t.Errorf("SetExit: masked cell: want error, got nil")
+	}
+}
+
+func TestAddEntranceAndExit(t *testing.T) {
+	r := branchMaze(t)
+	// solved returns the path that every solver finds, failing the test if they disagree on its length
+	solved := func() [][2]int {
+		t.Helper()
+		if err := r.SolveBFS(); err != nil {
+			t.Fatalf("SolveBFS: %v", err)
+		}
+		path := r.SolutionPath()
+		checkPath(t, r, path)
+		for _, tc := range []struct {
+			name  string
+			solve func() error
+		}{
+			{"Solve", r.Solve},
+			{"SolveAStar", r.SolveAStar},
+			{"SolveDijkstra", r.SolveDijkstra},
+		} {
+			r.ResetSolution()
+			if err := tc.solve(); err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			} else if got := r.SolutionPath(); len(got) != len(path) {
+				t.Errorf("%s: want %v, got %v", tc.name, path, got)
+			}
+		}
+		return path
+	}
+
+	// the new entrance at the end of the northern row is farther from the exit than the old one
+	if err := r.AddEntrance(0, 2); err != nil {
+		t.Fatalf("AddEntrance: %v", err)
+	}
+	if want, got := [][2]int{{0, 0}, {1, 0}, {1, 1}, {1, 2}, {2, 2}}, solved(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AddEntrance: want %v, got %v", want, got)
+	}
+	// the new exit is nearer to the first entrance than the old exit
+	if err := r.AddExit(2, 1); err != nil {
+		t.Fatalf("AddExit: %v", err)
+	}
+	if want, got := [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}}, solved(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AddExit: want %v, got %v", want, got)
+	}
+	// and an entrance next to the new exit is nearest of all
+	if err := r.AddEntrance(2, 0); err != nil {
+		t.Fatalf("AddEntrance: %v", err)
+	}
+	if want, got := [][2]int{{2, 0}, {2, 1}}, solved(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AddEntrance: want %v, got %v", want, got)
+	}
+
+	// adding a gate again does nothing
+	if err := r.AddEntrance(0, 0); err != nil {
+		t.Errorf("AddEntrance: again: %v", err)
+	} else if err := r.AddExit(2, 1); err != nil {
+		t.Errorf("AddExit: again: %v", err)
+	}
+	if len(r.entrances) != 3 || len(r.exits) != 2 {
+		t.Errorf("gates: want 3 entrances and 2 exits, got %d and %d", len(r.entrances), len(r.exits))
+	}
+	want := []outerOpening{{0, 0, North}, {0, 2, North}, {2, 0, West}, {2, 1, South}, {2, 2, South}}
+	if got := outerOpenings(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("gates: openings: want %v, got %v", want, got)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		add      func(row, col int) error
+		row, col int
+	}{
+		{"AddEntrance: not on an edge", r.AddEntrance, 1, 1},
+		{"AddEntrance: out of bounds", r.AddEntrance, 0, 3},
+		{"AddEntrance: an exit", r.AddEntrance, 2, 2},
+		{"AddExit: an entrance", r.AddExit, 0, 2},
+	} {
+		if err := tc.add(tc.row, tc.col); err == nil {
+			t.Errorf("%s: want error, got nil", tc.name)
+		}
+	}
+}
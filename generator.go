@@ -0,0 +1,285 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+)
+
+// Generator carves the passages of a maze.
+// Carve is given a grid where every wall is closed and should use rng for every random choice,
+// so that mazes can be reproduced from a seed. the grid is always at least 2 x 2.
+// the generator should connect every cell; otherwise RectangleMazeWith can't solve the maze.
+type Generator interface {
+	Carve(g *Grid, rng *rand.Rand)
+}
+
+// RectangleMazeWith creates a maze, using the generator to carve the passages.
+// the entrance and exit are placed like RectangleMaze does unless an option changes them.
+// every constructor in the package is a wrapper around this one.
+// it returns an error if the dimensions are too small, if a context from WithContext is cancelled
+// while the maze is being carved, or if solve is set and the generator didn't connect the entrance to the exit.
+func RectangleMazeWith(height, width int, gen Generator, solve bool, opts ...Option) (*Rectangle, error) {
+	cfg := options{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if !cfg.seeded {
+		cfg.seed = rand.Int63()
+	}
+	if cfg.toroidal {
+		if height < 3 || width < 3 {
+			return nil, fmt.Errorf("invalid dimensions %d x %d: a toroidal maze needs at least 3 x 3 cells", height, width)
+		}
+	} else if err := validateDimensions(height, width); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(cfg.seed))
+	gates := gateSource(rng)
+	g := &Grid{g: createGrid(height, width)}
+	if cfg.toroidal {
+		g.g = createToroidalGrid(height, width)
+	}
+	if cfg.mask != nil {
+		if err := g.g.applyMask(cfg.mask); err != nil {
+			return nil, err
+		}
+	}
+
+	// report each cell as it is added to the maze
+	var steps []carveStep
+	if cfg.progress != nil || cfg.record {
+		done, total := 0, height*width
+		g.g.onCarve = func(from, to *cell) {
+			if cfg.record {
+				steps = append(steps, carveStep{from: from, to: to})
+			}
+			if cfg.progress != nil {
+				done++
+				cfg.progress(done, total)
+			}
+		}
+	}
+	if cg, ok := gen.(contextGenerator); ok {
+		if err := cg.carveContext(cfg.ctx, g, rng); err != nil {
+			return nil, err
+		}
+	} else if gen.Carve(g, rng); cfg.ctx.Err() != nil {
+		return nil, cfg.ctx.Err()
+	}
+	g.g.onCarve = nil
+
+	var entrance, exit *cell
+	switch {
+	case cfg.mask != nil:
+		entrance, exit = placeMaskedGates(g.g)
+	case cfg.gateOptions != nil:
+		// window returns the number of cells that the gate can be picked from, which is always at least one
+		window := func(fraction float64) int {
+			return max(1, int(min(max(fraction, 0), 1)*float64(width)))
+		}
+		entrance, exit = placeGatesWithin(g.g, gates, window(cfg.gateOptions.EntranceEdgeFraction), window(cfg.gateOptions.ExitEdgeFraction))
+	default:
+		entrance, exit = placeGatesFor(g.g, gates, cfg.placement)
+	}
+
+	r := &Rectangle{
+		g:         g.g,
+		entrances: []*cell{entrance},
+		exits:     []*cell{exit},
+		seed:      cfg.seed,
+		algorithm: algorithmOf(gen),
+		steps:     steps,
+	}
+	if solve {
+		if err := r.Solve(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// RectangleMazeWithSeed creates a maze like RectangleMazeWith, using a source created from the seed
+// for every random choice. the same dimensions, generator, and seed always create the same maze.
+func RectangleMazeWithSeed(height, width int, gen Generator, seed int64, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, gen, solve, WithSeed(seed))
+}
+
+// Option changes how RectangleMazeWith creates a maze.
+type Option func(*options)
+
+// options holds the settings changed by the options.
+type options struct {
+	seed        int64
+	seeded      bool
+	ctx         context.Context
+	progress    func(done, total int)
+	placement   GatePlacement
+	gateOptions *GateOptions
+	// record, mask, and toroidal are set by the package's own constructors
+	record   bool
+	mask     [][]bool
+	toroidal bool
+}
+
+// WithSeed creates the maze from the seed instead of a seed taken from the global source.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed, o.seeded = seed, true
+	}
+}
+
+// WithContext stops carving and returns the context's error if the context is cancelled.
+// Wilson's algorithm checks the context while it carves; other generators are checked when they finish.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithProgress calls progress each time a cell is added to the maze, like RectangleMazeProgress.
+// only generators that report their progress call it.
+func WithProgress(progress func(done, total int)) Option {
+	return func(o *options) {
+		o.progress = progress
+	}
+}
+
+// WithGatePlacement places the entrance and exit according to the placement, like RectangleMazeWithGates.
+func WithGatePlacement(placement GatePlacement) Option {
+	return func(o *options) {
+		o.placement, o.gateOptions = placement, nil
+	}
+}
+
+// WithGateOptions places the entrance and exit within the parts of their edges given by the options,
+// like RectangleMazeWithGateOptions.
+func WithGateOptions(opts GateOptions) Option {
+	return func(o *options) {
+		o.placement, o.gateOptions = OppositeEdges, &opts
+	}
+}
+
+// withSteps records every step of the carving for RenderGIF.
+func withSteps() Option {
+	return func(o *options) {
+		o.record = true
+	}
+}
+
+// withMask leaves the cells that are off in the mask out of the maze. the mask must match the dimensions.
+func withMask(mask [][]bool) Option {
+	return func(o *options) {
+		o.mask = mask
+	}
+}
+
+// withToroidal links the cells on each edge to the cells on the opposite edge.
+func withToroidal() Option {
+	return func(o *options) {
+		o.toroidal = true
+	}
+}
+
+// contextGenerator is implemented by generators that check a context while they carve.
+type contextGenerator interface {
+	carveContext(ctx context.Context, g *Grid, rng *rand.Rand) error
+}
+
+// algorithmOf returns the name reported by Algorithm for mazes carved by the generator.
+// generators from outside the package are named for their type.
+func algorithmOf(gen Generator) string {
+	switch gen.(type) {
+	case WilsonGenerator:
+		return "wilson"
+	case AldousBroderGenerator:
+		return "aldous-broder"
+	case BinaryTreeGenerator:
+		return "binary-tree"
+	case EllerGenerator:
+		return "eller"
+	case HuntAndKillGenerator:
+		return "hunt-and-kill"
+	case KruskalGenerator:
+		return "kruskal"
+	case RecursiveDivisionGenerator:
+		return "recursive-division"
+	case SidewinderGenerator:
+		return "sidewinder"
+	case WeaveGenerator:
+		return "weave"
+	case SymmetricGenerator:
+		return "symmetric"
+	case tileGenerator:
+		return "tiled"
+	}
+	return fmt.Sprintf("%T", gen)
+}
+
+// WilsonGenerator carves mazes with Wilson's algorithm, like RectangleMaze.
+type WilsonGenerator struct {
+	// Weights biases the random walks toward some directions.
+	// the zero value carves uniformly random mazes.
+	Weights DirectionWeights
+}
+
+func (gen WilsonGenerator) Carve(g *Grid, rng *rand.Rand) {
+	_ = gen.carveContext(context.Background(), g, rng)
+}
+
+func (gen WilsonGenerator) carveContext(ctx context.Context, g *Grid, rng *rand.Rand) error {
+	g.g.bias = gen.Weights
+	defer func() { g.g.bias = DirectionWeights{} }()
+	return g.g.carveWilsonContext(ctx, rng)
+}
+
+// AldousBroderGenerator carves mazes with the Aldous-Broder algorithm, like RectangleAldousBroder.
+type AldousBroderGenerator struct {
+	// Weights biases the random walk toward some directions.
+	// the zero value carves uniformly random mazes.
+	Weights DirectionWeights
+}
+
+func (gen AldousBroderGenerator) Carve(g *Grid, rng *rand.Rand) {
+	g.g.bias = gen.Weights
+	g.g.carveAldousBroder(rng)
+	g.g.bias = DirectionWeights{}
+}
+
+// BinaryTreeGenerator carves mazes with the binary tree algorithm, like RectangleBinaryTree.
+type BinaryTreeGenerator struct{}
+
+func (BinaryTreeGenerator) Carve(g *Grid, rng *rand.Rand) { g.g.carveBinaryTree(rng) }
+
+// EllerGenerator carves mazes with Eller's algorithm, like RectangleEller.
+type EllerGenerator struct{}
+
+func (EllerGenerator) Carve(g *Grid, rng *rand.Rand) { g.g.carveEller(rng) }
+
+// HuntAndKillGenerator carves mazes with the hunt-and-kill algorithm, like RectangleHuntAndKill.
+type HuntAndKillGenerator struct{}
+
+func (HuntAndKillGenerator) Carve(g *Grid, rng *rand.Rand) { g.g.carveHuntAndKill(rng) }
+
+// KruskalGenerator carves mazes with randomized Kruskal's algorithm, like RectangleKruskal.
+type KruskalGenerator struct{}
+
+func (KruskalGenerator) Carve(g *Grid, rng *rand.Rand) { g.g.carveKruskal(rng) }
+
+// RecursiveDivisionGenerator builds mazes with the recursive division algorithm, like RectangleRecursiveDivision.
+type RecursiveDivisionGenerator struct{}
+
+func (RecursiveDivisionGenerator) Carve(g *Grid, rng *rand.Rand) { g.g.carveRecursiveDivision(rng) }
+
+// SidewinderGenerator carves mazes with the sidewinder algorithm, like RectangleSidewinder.
+type SidewinderGenerator struct{}
+
+func (SidewinderGenerator) Carve(g *Grid, rng *rand.Rand) { g.g.carveSidewinder(rng) }
+
+// WeaveGenerator carves weave mazes with tunnels under some cells, like RectangleWeave.
+type WeaveGenerator struct{}
+
+func (WeaveGenerator) Carve(g *Grid, rng *rand.Rand) { g.g.carveWeave(rng) }
@@ -0,0 +1,214 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRectangleMazeWithOptions(t *testing.T) {
+	a, err := RectangleMazeWith(6, 9, WilsonGenerator{}, false, WithSeed(11))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	b, err := RectangleMazeWithSeed(6, 9, WilsonGenerator{}, 11, false)
+	if err != nil {
+		t.Fatalf("RectangleMazeWithSeed: %v", err)
+	}
+	if renderText(t, a) != renderText(t, b) || a.Seed() != 11 || a.Algorithm() != "wilson" {
+		t.Errorf("WithSeed: mazes from the same seed differ")
+	}
+
+	calls := 0
+	if _, err := RectangleMazeWith(6, 9, WilsonGenerator{}, false, WithProgress(func(done, total int) {
+		calls++
+		if done != calls || total != 54 {
+			t.Fatalf("WithProgress: want %d of 54, got %d of %d", calls, done, total)
+		}
+	})); err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	} else if calls != 54 {
+		t.Errorf("WithProgress: want 54 calls, got %d", calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, gen := range []Generator{WilsonGenerator{}, KruskalGenerator{}} {
+		if _, err := RectangleMazeWith(6, 9, gen, false, WithContext(ctx)); !errors.Is(err, context.Canceled) {
+			t.Errorf("%T: WithContext: want %v, got %v", gen, context.Canceled, err)
+		}
+	}
+
+	r, err := RectangleMazeWith(6, 9, KruskalGenerator{}, true, WithGatePlacement(OppositeCorners))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	if row, col := r.Entrance(); row != 0 || col != 0 {
+		t.Errorf("WithGatePlacement: entrance: want (0, 0), got (%d, %d)", row, col)
+	}
+	if row, col := r.Exit(); row != 5 || col != 8 {
+		t.Errorf("WithGatePlacement: exit: want (5, 8), got (%d, %d)", row, col)
+	}
+}
+
+// checkPerfect generates seeded mazes of several sizes with the generator and fails the test unless
+// every maze is perfect, with exactly one path between any two cells, and the same seed gives the same maze.
+func checkPerfect(t *testing.T, gen Generator, algorithm string) {
+	t.Helper()
+	for _, size := range [][2]int{{2, 2}, {2, 9}, {9, 2}, {8, 8}, {13, 21}} {
+		r, err := RectangleMazeWith(size[0], size[1], gen, true, WithSeed(int64(size[0]*size[1])))
+		if err != nil {
+			t.Fatalf("%s: %d x %d: %v", algorithm, size[0], size[1], err)
+		}
+		if !spanningTree(r.g.allCells(), (*cell).openNeighbors) {
+			t.Errorf("%s: %d x %d: maze is not perfect:\n%s", algorithm, size[0], size[1], renderText(t, r))
+		}
+		if r.Algorithm() != algorithm {
+			t.Errorf("%s: Algorithm: want %q, got %q", algorithm, algorithm, r.Algorithm())
+		}
+		if path := r.SolutionPath(); len(path) == 0 {
+			t.Errorf("%s: %d x %d: want a solution, got none", algorithm, size[0], size[1])
+		}
+		again, err := RectangleMazeWith(size[0], size[1], gen, true, WithSeed(int64(size[0]*size[1])))
+		if err != nil {
+			t.Fatalf("%s: %d x %d: %v", algorithm, size[0], size[1], err)
+		} else if renderText(t, again) != renderText(t, r) {
+			t.Errorf("%s: %d x %d: same seed gave different mazes", algorithm, size[0], size[1])
+		}
+	}
+}
+
+func TestKruskalGenerator(t *testing.T) {
+	checkPerfect(t, KruskalGenerator{}, "kruskal")
+
+	r, err := RectangleKruskal(12, 17, true)
+	if err != nil {
+		t.Fatalf("RectangleKruskal: %v", err)
+	}
+	if !r.IsPerfect() {
+		t.Errorf("RectangleKruskal: maze is not perfect")
+	}
+}
+
+func TestEllerGenerator(t *testing.T) {
+	checkPerfect(t, EllerGenerator{}, "eller")
+}
+
+func TestBinaryTreeGenerator(t *testing.T) {
+	checkPerfect(t, BinaryTreeGenerator{}, "binary-tree")
+
+	// every cell links north or east, so the top row and the right column are open corridors
+	r, err := RectangleMazeWith(10, 10, BinaryTreeGenerator{}, false, WithSeed(3))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	for n := 0; n < 9; n++ {
+		if r.g.cells[0][n].walls.east {
+			t.Errorf("binary-tree: top row: wall east of column %d", n)
+		}
+		if r.g.cells[n+1][9].walls.north {
+			t.Errorf("binary-tree: right column: wall north of row %d", n+1)
+		}
+	}
+}
+
+func TestSidewinderGenerator(t *testing.T) {
+	checkPerfect(t, SidewinderGenerator{}, "sidewinder")
+
+	// runs can't end on the top row, so it is a single open corridor
+	r, err := RectangleMazeWith(10, 10, SidewinderGenerator{}, false, WithSeed(3))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	for col := 0; col < 9; col++ {
+		if r.g.cells[0][col].walls.east {
+			t.Errorf("sidewinder: top row: wall east of column %d", col)
+		}
+	}
+}
+
+func TestAldousBroderGenerator(t *testing.T) {
+	checkPerfect(t, AldousBroderGenerator{}, "aldous-broder")
+
+	// a 2 x 2 grid has four spanning trees, each missing one of the four inner walls.
+	// the algorithm is unbiased, so each tree should be carved about a quarter of the time.
+	const mazes = 4000
+	counts := map[string]int{}
+	for seed := int64(0); seed < mazes; seed++ {
+		r, err := RectangleMazeWith(2, 2, AldousBroderGenerator{}, false, WithSeed(seed))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		counts[renderText(t, r)]++
+	}
+	if len(counts) != 4 {
+		t.Fatalf("aldous-broder: want 4 different 2 x 2 mazes, got %d", len(counts))
+	}
+	for text, n := range counts {
+		if n < mazes/4-150 || n > mazes/4+150 {
+			t.Errorf("aldous-broder: want about %d of each maze, got %d of\n%s", mazes/4, n, text)
+		}
+	}
+}
+
+func TestHuntAndKillGenerator(t *testing.T) {
+	checkPerfect(t, HuntAndKillGenerator{}, "hunt-and-kill")
+}
+
+func TestRecursiveDivisionGenerator(t *testing.T) {
+	checkPerfect(t, RecursiveDivisionGenerator{}, "recursive-division")
+
+	// every wall is added with a gap in it, so no cell is closed in on all four sides
+	r, err := RectangleMazeWith(16, 16, RecursiveDivisionGenerator{}, false, WithSeed(1))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	for _, c := range r.g.allCells() {
+		if len(c.openNeighbors()) == 0 {
+			t.Errorf("recursive-division: cell (%d, %d) is enclosed", c.row, c.col)
+		}
+	}
+}
+
+// passages returns the number of open passages that run east-west and north-south.
+func passages(r *Rectangle) (horizontal, vertical int) {
+	for _, c := range r.g.allCells() {
+		if c.eastIsOpen() {
+			horizontal++
+		}
+		if c.southIsOpen() {
+			vertical++
+		}
+	}
+	return horizontal, vertical
+}
+
+func TestDirectionWeights(t *testing.T) {
+	grain := DirectionWeights{North: 1, East: 8, South: 1, West: 8}
+	for _, gen := range []Generator{
+		WilsonGenerator{Weights: grain},
+		AldousBroderGenerator{Weights: grain},
+	} {
+		r, err := RectangleMazeWith(30, 30, gen, false, WithSeed(1))
+		if err != nil {
+			t.Fatalf("%T: RectangleMazeWith: %v", gen, err)
+		}
+		if !r.IsPerfect() {
+			t.Errorf("%T: weighted maze is not perfect", gen)
+		}
+		if horizontal, vertical := passages(r); horizontal < 2*vertical {
+			t.Errorf("%T: want a horizontal grain, got %d horizontal and %d vertical passages", gen, horizontal, vertical)
+		}
+	}
+
+	// without weights, neither direction is favored much
+	r, err := RectangleMazeWith(30, 30, WilsonGenerator{}, false, WithSeed(1))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	if horizontal, vertical := passages(r); horizontal > 2*vertical || vertical > 2*horizontal {
+		t.Errorf("uniform: want no grain, got %d horizontal and %d vertical passages", horizontal, vertical)
+	}
+}
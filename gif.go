@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+)
+
+// carveStep records a single cell being added to the maze.
+// to is the cell it was linked to, or nil if it was added without a passage.
+type carveStep struct {
+	from, to *cell
+}
+
+// RectangleMazeAnimated creates a maze like RectangleMaze, but records every step
+// of the carving so that the maze can be rendered by RenderGIF.
+func RectangleMazeAnimated(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, WilsonGenerator{}, solve, withSteps())
+}
+
+// gifPalette holds the colors for the animation. the order must match the gif color constants.
+var gifPalette = color.Palette{color.White, color.Gray{Y: 0xc0}, color.Black}
+
+// indexes into gifPalette
+const (
+	gifIn uint8 = iota
+	gifOut
+	gifWall
+)
+
+// RenderGIF renders the carving of the maze as an animated GIF, with one frame for every cell added.
+// cells that haven't been added are gray. delay is the time between frames in hundredths of a second.
+// it returns an error if the maze was not created by RectangleMazeAnimated.
+func (r *Rectangle) RenderGIF(w io.Writer, scale int, delay int) error {
+	if len(r.steps) == 0 {
+		return fmt.Errorf("gif: maze has no recorded steps")
+	} else if scale < 3 {
+		return fmt.Errorf("gif: scale must be at least 3")
+	}
+	gutter := scale / 2
+	height, width := r.g.height*scale+gutter*2+1, r.g.width*scale+gutter*2+1
+
+	// cellBounds returns the rectangle for the cell, including the pixels for the walls
+	cellBounds := func(c *cell) image.Rectangle {
+		x, y := c.col*scale+gutter, c.row*scale+gutter
+		return image.Rect(x, y, x+scale+1, y+scale+1)
+	}
+
+	// the canvas starts with every cell out of the maze and every wall standing
+	canvas := image.NewPaletted(image.Rect(0, 0, width, height), gifPalette)
+	for _, c := range r.g.allCells() {
+		b := cellBounds(c)
+		fill(canvas, b, gifWall)
+		fill(canvas, b.Inset(1), gifOut)
+	}
+
+	anim := &gif.GIF{
+		Config: image.Config{ColorModel: gifPalette, Width: width, Height: height},
+	}
+	addFrame := func(bounds image.Rectangle, delay int) {
+		frame := image.NewPaletted(bounds, gifPalette)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			copy(frame.Pix[frame.PixOffset(bounds.Min.X, y):frame.PixOffset(bounds.Max.X, y)],
+				canvas.Pix[canvas.PixOffset(bounds.Min.X, y):canvas.PixOffset(bounds.Max.X, y)])
+		}
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, delay)
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+	}
+	addFrame(canvas.Bounds(), delay)
+
+	// every following frame only contains the pixels that changed
+	for _, step := range r.steps {
+		bounds := cellBounds(step.from)
+		fill(canvas, bounds.Inset(1), gifIn)
+		if step.to != nil {
+			// remove the wall between the cells, leaving the corners. the other cell is only filled
+			// by its own step, since a walk links each cell to the next one before that one is added.
+			to := cellBounds(step.to)
+			wall := bounds.Intersect(to)
+			if wall.Dx() == 1 {
+				wall.Min.Y, wall.Max.Y = wall.Min.Y+1, wall.Max.Y-1
+			} else {
+				wall.Min.X, wall.Max.X = wall.Min.X+1, wall.Max.X-1
+			}
+			fill(canvas, wall, gifIn)
+			bounds = bounds.Union(wall)
+		}
+		addFrame(bounds, delay)
+	}
+
+	// open the entrances and exits and hold the final frame
+	for _, c := range append(append([]*cell{}, r.entrances...), r.exits...) {
+		b := cellBounds(c)
+		if !c.walls.north {
+			fill(canvas, image.Rect(b.Min.X+1, b.Min.Y, b.Max.X-1, b.Min.Y+1), gifIn)
+		}
+		if !c.walls.east {
+			fill(canvas, image.Rect(b.Max.X-1, b.Min.Y+1, b.Max.X, b.Max.Y-1), gifIn)
+		}
+		if !c.walls.south {
+			fill(canvas, image.Rect(b.Min.X+1, b.Max.Y-1, b.Max.X-1, b.Max.Y), gifIn)
+		}
+		if !c.walls.west {
+			fill(canvas, image.Rect(b.Min.X, b.Min.Y+1, b.Min.X+1, b.Max.Y-1), gifIn)
+		}
+	}
+	addFrame(canvas.Bounds(), delay*50)
+
+	return gif.EncodeAll(w, anim)
+}
+
+// fill sets every pixel in the rectangle to the color index.
+func fill(img *image.Paletted, r image.Rectangle, index uint8) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetColorIndex(x, y, index)
+		}
+	}
+}
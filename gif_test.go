@@ -0,0 +1,79 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"testing"
+)
+
+func TestRenderGIF(t *testing.T) {
+	const height, width, scale, gutter = 5, 6, 10, 5
+	r, err := RectangleMazeAnimated(height, width, false)
+	if err != nil {
+		t.Fatalf("RectangleMazeAnimated: %v", err)
+	}
+	var b bytes.Buffer
+	if err := r.RenderGIF(&b, scale, 2); err != nil {
+		t.Fatalf("RenderGIF: %v", err)
+	}
+	anim, err := gif.DecodeAll(&b)
+	if err != nil {
+		t.Fatalf("gif: %v", err)
+	}
+	// one frame for the empty grid, one for each cell as it is added, and one that holds the finished maze
+	if want := height*width + 2; len(anim.Image) != want {
+		t.Fatalf("RenderGIF: want %d frames, got %d", want, len(anim.Image))
+	}
+	if last := anim.Delay[len(anim.Delay)-1]; last != 100 {
+		t.Errorf("RenderGIF: last frame: want a delay of 100, got %d", last)
+	}
+
+	// the frames only hold the pixels that changed, so draw each one over the ones before it
+	canvas := image.NewRGBA(image.Rect(0, 0, anim.Config.Width, anim.Config.Height))
+	white := func(x, y int) bool {
+		return color.RGBAModel.Convert(canvas.At(x, y)) == color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	for n, frame := range anim.Image {
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		// every frame adds one cell, so the number of cells in the maze grows by one each time
+		in := 0
+		for row := 0; row < height; row++ {
+			for col := 0; col < width; col++ {
+				if white(col*scale+gutter+scale/2, row*scale+gutter+scale/2) {
+					in++
+				}
+			}
+		}
+		if want := min(n, height*width); in != want {
+			t.Errorf("RenderGIF: frame %d: want %d cells in the maze, got %d", n, want, in)
+		}
+	}
+
+	// the last frame shows the walls of the finished maze
+	for _, c := range r.g.allCells() {
+		x, y := c.col*scale+gutter, c.row*scale+gutter
+		if c.neighbors.east != nil && white(x+scale, y+scale/2) != c.eastIsOpen() {
+			t.Errorf("RenderGIF: (%d, %d): east wall: want open %v", c.row, c.col, c.eastIsOpen())
+		}
+		if c.neighbors.south != nil && white(x+scale/2, y+scale) != c.southIsOpen() {
+			t.Errorf("RenderGIF: (%d, %d): south wall: want open %v", c.row, c.col, c.southIsOpen())
+		}
+	}
+
+	plain, err := RectangleMazeWith(height, width, WilsonGenerator{}, false, WithSeed(1))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	if err := plain.RenderGIF(io.Discard, scale, 2); err == nil {
+		t.Errorf("RenderGIF: no recorded steps: want error, got nil")
+	}
+	if err := r.RenderGIF(io.Discard, 2, 2); err == nil {
+		t.Errorf("RenderGIF: scale 2: want error, got nil")
+	}
+}
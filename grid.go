@@ -2,11 +2,27 @@
 
 package maze
 
+import "fmt"
+
 // grid contains all the cells in the maze.
 type grid struct {
 	height int
 	width  int
 	cells  [][]*cell
+	// onCarve is an optional hook that is called when a cell is added to the maze.
+	// to is the cell that it was linked to, or nil if it was added without a passage.
+	onCarve func(from, to *cell)
+	// bias weights the directions that random walks pick while carving.
+	// the zero value picks uniformly.
+	bias DirectionWeights
+}
+
+// validateDimensions returns an error if the height or width is too small to make a maze.
+func validateDimensions(height, width int) error {
+	if height < 2 || width < 2 {
+		return fmt.Errorf("invalid dimensions %d x %d: a maze needs at least 2 x 2 cells", height, width)
+	}
+	return nil
 }
 
 // createGrid creates a new rectangular grid with the given height and width.
@@ -21,7 +37,7 @@ func createGrid(height, width int) *grid {
 	for row := 0; row < height; row++ {
 		g.cells[row] = make([]*cell, width)
 		for col := 0; col < width; col++ {
-			c := &cell{row: row, col: col}
+			c := &cell{row: row, col: col, weight: 1}
 			c.walls.north = true
 			c.walls.east = true
 			c.walls.south = true
@@ -77,12 +93,103 @@ func (g *grid) allCells() []*cell {
 	return cells
 }
 
-// clearWalk resets the cells in the grid to ready it for another walk.
-// it loops through all cells and updates the `to` field to nil.
-func (g *grid) clearWalk() {
+// hasCellsIn returns true if any cell in the grid has been added to the maze.
+func (g *grid) hasCellsIn() bool {
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			if g.cells[row][col].in {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inBounds returns true if the row and column are inside the grid.
+func (g *grid) inBounds(row, col int) bool {
+	return 0 <= row && row < g.height && 0 <= col && col < g.width
+}
+
+// at returns the cell at the given row and column.
+// it returns false if the coordinates are outside the grid.
+func (g *grid) at(row, col int) (*cell, bool) {
+	if !g.inBounds(row, col) {
+		return nil, false
+	}
+	return g.cells[row][col], true
+}
+
+// clone returns a copy of a rectangular grid with the same walls and cell flags.
+// the walk pointers and the solver's flags are not copied.
+func (g *grid) clone() *grid {
+	c := createGrid(g.height, g.width)
 	for row := 0; row < g.height; row++ {
 		for col := 0; col < g.width; col++ {
-			g.cells[row][col].to = nil
+			from, to := g.cells[row][col], c.cells[row][col]
+			to.walls, to.in, to.under, to.weight = from.walls, from.in, from.under, from.weight
 		}
 	}
+	return c
+}
+
+// clearSolution resets the cells in the grid to ready it for another search.
+// it clears the `visited`, `onPath`, and `to` fields of every cell.
+func (g *grid) clearSolution() {
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			c := g.cells[row][col]
+			c.visited, c.onPath, c.to = false, false, nil
+		}
+	}
+}
+
+// Grid is a rectangular grid of cells that callers can carve by hand.
+// every cell starts with all four walls closed. once it is carved, RectangleFromGrid
+// wraps it in a Rectangle so that it can be solved and rendered.
+type Grid struct {
+	g *grid
+}
+
+// NewGrid returns a new grid with the given height and width and every wall closed.
+// it returns nil if the grid is smaller than 2 x 2.
+func NewGrid(height, width int) *Grid {
+	if validateDimensions(height, width) != nil {
+		return nil
+	}
+	return &Grid{g: createGrid(height, width)}
+}
+
+// Dimensions returns the number of rows and columns in the grid.
+func (g *Grid) Dimensions() (height, width int) {
+	return g.g.height, g.g.width
+}
+
+// Neighbors returns the cells next to the given cell, in north, east, south, west order.
+// cells on an edge have fewer neighbors. it returns nil if the cell is out of bounds.
+func (g *Grid) Neighbors(row, col int) []CellInfo {
+	c, ok := g.g.at(row, col)
+	if !ok {
+		return nil
+	}
+	var neighbors []CellInfo
+	for _, neighbor := range c.neighborhood {
+		neighbors = append(neighbors, CellInfo{Row: neighbor.row, Col: neighbor.col})
+	}
+	return neighbors
+}
+
+// Carve removes the wall between two neighboring cells.
+// it returns an error if either cell is out of bounds or the cells are not neighbors.
+func (g *Grid) Carve(from, to [2]int) error {
+	for _, rc := range [][2]int{from, to} {
+		if !g.g.inBounds(rc[0], rc[1]) {
+			return fmt.Errorf("cell (%d, %d) is out of bounds", rc[0], rc[1])
+		}
+	}
+	a, b := g.g.cells[from[0]][from[1]], g.g.cells[to[0]][to[1]]
+	if !a.linkTo(b) {
+		return fmt.Errorf("cells (%d, %d) and (%d, %d) are not neighbors", from[0], from[1], to[0], to[1])
+	}
+	a.in, b.in = true, true
+	return nil
 }
@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+// serpentine returns a grid carved as a single path that runs east along the first row,
+// west along the second, and so on.
+func serpentine(t *testing.T, height, width int) *Grid {
+	t.Helper()
+	g := NewGrid(height, width)
+	if g == nil {
+		t.Fatalf("NewGrid(%d, %d): got nil", height, width)
+	}
+	for row := 0; row < height; row++ {
+		for col := 0; col+1 < width; col++ {
+			if err := g.Carve([2]int{row, col}, [2]int{row, col + 1}); err != nil {
+				t.Fatalf("Carve: %v", err)
+			}
+		}
+		if row+1 < height {
+			col := width - 1
+			if row%2 == 1 {
+				col = 0
+			}
+			if err := g.Carve([2]int{row, col}, [2]int{row + 1, col}); err != nil {
+				t.Fatalf("Carve: %v", err)
+			}
+		}
+	}
+	return g
+}
+
+func TestNewGrid(t *testing.T) {
+	for _, hw := range [][2]int{{0, 0}, {1, 5}, {5, 1}, {-2, 3}} {
+		if g := NewGrid(hw[0], hw[1]); g != nil {
+			t.Errorf("NewGrid(%d, %d): want nil, got grid", hw[0], hw[1])
+		}
+	}
+	g := NewGrid(3, 4)
+	if height, width := g.Dimensions(); height != 3 || width != 4 {
+		t.Errorf("Dimensions: want 3 x 4, got %d x %d", height, width)
+	}
+	if n := g.Neighbors(0, 0); len(n) != 2 || n[0] != (CellInfo{0, 1}) || n[1] != (CellInfo{1, 0}) {
+		t.Errorf("Neighbors(0, 0): want [{0 1} {1 0}], got %v", n)
+	}
+	if n := g.Neighbors(1, 1); len(n) != 4 {
+		t.Errorf("Neighbors(1, 1): want 4 neighbors, got %v", n)
+	}
+	if n := g.Neighbors(3, 0); n != nil {
+		t.Errorf("Neighbors(3, 0): want nil, got %v", n)
+	}
+}
+
+func TestGridCarve(t *testing.T) {
+	g := NewGrid(3, 3)
+	if err := g.Carve([2]int{1, 1}, [2]int{1, 2}); err != nil {
+		t.Fatalf("Carve: %v", err)
+	}
+	a, b := g.g.cells[1][1], g.g.cells[1][2]
+	if a.walls.east || b.walls.west {
+		t.Errorf("Carve: walls between (1, 1) and (1, 2) are still closed")
+	}
+	if err := g.Carve([2]int{0, 0}, [2]int{1, 1}); err == nil {
+		t.Errorf("Carve: diagonal cells: want error, got nil")
+	}
+	if err := g.Carve([2]int{0, 0}, [2]int{-1, 0}); err == nil {
+		t.Errorf("Carve: out of bounds: want error, got nil")
+	}
+}
+
+func TestRectangleFromGrid(t *testing.T) {
+	g := serpentine(t, 3, 4)
+	first := RectangleFromGrid(g, true)
+	second := RectangleFromGrid(g, true)
+	for n, r := range []*Rectangle{first, second} {
+		if len(r.entrances) != 1 || len(r.exits) != 1 {
+			t.Errorf("maze %d: want 1 entrance and 1 exit, got %d and %d", n, len(r.entrances), len(r.exits))
+		}
+		if got := len(outerOpenings(r)); got != 2 {
+			t.Errorf("maze %d: want 2 openings in the border, got %d", n, got)
+		}
+		if got := r.SolutionLength(); got != 12 {
+			t.Errorf("maze %d: SolutionLength: want 12, got %d", n, got)
+		}
+	}
+
+	// the grid itself is left without gates, and carving it again doesn't change the mazes
+	for _, c := range g.g.allCells() {
+		if c.entrance || c.exit || c.onPath {
+			t.Errorf("grid: cell (%d, %d) was changed by RectangleFromGrid", c.row, c.col)
+		}
+	}
+	if err := g.Carve([2]int{0, 0}, [2]int{1, 0}); err != nil {
+		t.Fatalf("Carve: %v", err)
+	}
+	if !first.IsPerfect() {
+		t.Errorf("IsPerfect: carving the grid changed the maze")
+	}
+
+	if r := RectangleFromGrid(nil, false); r != nil {
+		t.Errorf("RectangleFromGrid(nil): want nil, got maze")
+	}
+}
+
+func TestRectangleFromGridNoPath(t *testing.T) {
+	// nothing is carved, so there is no path and the maze is left unsolved
+	r := RectangleFromGrid(NewGrid(2, 2), true)
+	if path := r.SolutionPath(); path != nil {
+		t.Errorf("SolutionPath: want nil, got %v", path)
+	}
+}
+
+func TestInBounds(t *testing.T) {
+	r, err := RectangleMazeWith(4, 7, WilsonGenerator{}, false, WithSeed(1))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	for _, tc := range []struct {
+		row, col int
+		want     bool
+	}{
+		{0, 0, true},
+		{3, 6, true},
+		{3, 0, true},
+		{0, 6, true},
+		{4, 0, false},
+		{0, 7, false},
+		{-1, 0, false},
+		{0, -1, false},
+		{-1, -1, false},
+		{4, 7, false},
+	} {
+		if got := r.InBounds(tc.row, tc.col); got != tc.want {
+			t.Errorf("InBounds(%d, %d): want %v, got %v", tc.row, tc.col, tc.want, got)
+		}
+		c, ok := r.g.at(tc.row, tc.col)
+		if ok != tc.want || (ok && (c.row != tc.row || c.col != tc.col)) || (!ok && c != nil) {
+			t.Errorf("at(%d, %d): want %v, got %v", tc.row, tc.col, tc.want, ok)
+		}
+		// the exported methods that take coordinates don't panic outside the grid
+		n, e, s, w := r.CellOpenings(tc.row, tc.col)
+		if !tc.want && (n || e || s || w) {
+			t.Errorf("CellOpenings(%d, %d): want every side closed", tc.row, tc.col)
+		}
+		if got := r.DistancesFrom(tc.row, tc.col); (got != nil) != tc.want {
+			t.Errorf("DistancesFrom(%d, %d): want a field %v, got %v", tc.row, tc.col, tc.want, got != nil)
+		}
+	}
+}
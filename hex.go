@@ -0,0 +1,233 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"context"
+	svgo "github.com/ajstarks/svgo"
+	"github.com/fogleman/gg"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// hex directions, in clockwise order starting from north.
+// the opposite of a direction is three steps away.
+const (
+	hexNorth = iota
+	hexNorthEast
+	hexSouthEast
+	hexSouth
+	hexSouthWest
+	hexNorthWest
+)
+
+// hexCell is a cell in a hexagonal grid.
+// the hexagons are flat on top and odd columns are shifted down half a cell.
+type hexCell struct {
+	row, col int
+	// neighbors and walls are indexed by the hex direction
+	neighbors [6]*hexCell
+	walls     [6]bool
+	// set of all neighbors
+	neighborhood []*hexCell
+	// entrance is set to true if the cell is an entrance
+	entrance bool
+	// exit is set to true if the cell is an exit
+	exit bool
+	// in is set to true if the cell has been added to the maze
+	in bool
+	// to points the last cell visited in the walk
+	to *hexCell
+}
+
+// linkTo removes the walls between the cell and its neighbor.
+// it returns false, and leaves the walls alone, if the other cell is not a neighbor.
+func (c *hexCell) linkTo(other *hexCell) bool {
+	for dir, neighbor := range c.neighbors {
+		if neighbor == other {
+			c.walls[dir] = false
+			other.walls[(dir+3)%6] = false
+			return true
+		}
+	}
+	return false
+}
+
+// hexGrid contains all the cells in a hexagonal maze.
+type hexGrid struct {
+	height int
+	width  int
+	cells  [][]*hexCell
+}
+
+// createHexGrid creates a new grid of hexagons with the given height and width.
+func createHexGrid(height, width int) *hexGrid {
+	g := &hexGrid{
+		height: height,
+		width:  width,
+		cells:  make([][]*hexCell, height),
+	}
+
+	// allocate memory for all the cells in the grid
+	for row := 0; row < height; row++ {
+		g.cells[row] = make([]*hexCell, width)
+		for col := 0; col < width; col++ {
+			c := &hexCell{row: row, col: col}
+			c.walls = [6]bool{true, true, true, true, true, true}
+			g.cells[row][col] = c
+		}
+	}
+
+	// link neighboring cells. the diagonal neighbors depend on whether the column is shifted down.
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			c := g.cells[row][col]
+			// north and south are in the same column.
+			// the row of the upper diagonal neighbors is the same row for odd columns and one row up for even columns.
+			upper := row - 1
+			if col%2 == 1 {
+				upper = row
+			}
+			offsets := [6][2]int{
+				hexNorth:     {row - 1, col},
+				hexNorthEast: {upper, col + 1},
+				hexSouthEast: {upper + 1, col + 1},
+				hexSouth:     {row + 1, col},
+				hexSouthWest: {upper + 1, col - 1},
+				hexNorthWest: {upper, col - 1},
+			}
+			for dir, offset := range offsets {
+				nRow, nCol := offset[0], offset[1]
+				if nRow < 0 || nRow >= height || nCol < 0 || nCol >= width {
+					continue
+				}
+				neighbor := g.cells[nRow][nCol]
+				c.neighbors[dir] = neighbor
+				c.neighborhood = append(c.neighborhood, neighbor)
+			}
+		}
+	}
+
+	return g
+}
+
+// Hex is a maze made from hexagonal cells.
+type Hex struct {
+	g        *hexGrid
+	entrance *hexCell
+	exit     *hexCell
+}
+
+// HexMaze creates a maze of hexagonal cells using Wilson's algorithm.
+func HexMaze(height, width int) (*Hex, error) {
+	if err := validateDimensions(height, width); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(rand.Int63()))
+	g := createHexGrid(height, width)
+
+	// carve the maze with wilson's algorithm, which cannot fail without a context to cancel
+	var cells []*hexCell
+	for row := 0; row < height; row++ {
+		cells = append(cells, g.cells[row]...)
+	}
+	_ = wilsonGraph[*hexCell]{
+		cells: cells,
+		in:    func(c *hexCell) bool { return c.in },
+		add: func(from, to *hexCell) {
+			if to != nil {
+				from.linkTo(to)
+			}
+			from.in = true
+		},
+		neighbor: func(c *hexCell) *hexCell { return c.neighborhood[rng.Intn(len(c.neighborhood))] },
+		next:     func(c *hexCell) *hexCell { return c.to },
+		setNext:  func(c, next *hexCell) { c.to = next },
+	}.carve(context.Background(), rng)
+
+	// the entrance will be on the western part of the northern edge and
+	// the exit on the eastern part of the southern edge of the maze.
+	theGate := max(1, width/6)
+	entrance := g.cells[0][rng.Intn(theGate)]
+	entrance.entrance = true
+	entrance.walls[hexNorth] = false
+	exit := g.cells[height-1][width-1-rng.Intn(theGate)]
+	exit.exit = true
+	exit.walls[hexSouth] = false
+
+	return &Hex{g: g, entrance: entrance, exit: exit}, nil
+}
+
+// RenderPNG renders the maze as a PNG image. scale is the width of a cell.
+func (h *Hex) RenderPNG(w io.Writer, scale int) error {
+	height, width, lines := h.g.toLines(scale, scale/2)
+	dc := gg.NewContext(width, height)
+
+	// set the background of the image to white
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	// draw the walls
+	drawLines(dc, lines, PNGOptions{}.withDefaults())
+
+	// write the image as PNG
+	return dc.EncodePNG(w)
+}
+
+// RenderSVG renders the maze as an SVG image. scale is the width of a cell.
+func (h *Hex) RenderSVG(w io.Writer, scale int) error {
+	height, width, lines := h.g.toLines(scale, scale/2)
+	canvas := svgo.New(w)
+	canvas.Start(width, height)
+	canvas.Rect(0, 0, width, height, "fill:white")
+	for _, l := range lines {
+		canvas.Line(int(l.from.x), int(l.from.y), int(l.to.x), int(l.to.y), "stroke:black")
+	}
+	canvas.End()
+	return nil
+}
+
+// toLines renders the grid as a set of lines, one for each wall.
+// walls shared by two cells are only drawn once.
+func (g *hexGrid) toLines(scale int, gutter int) (height int, width int, lines []line) {
+	// size is the distance from the center of a hexagon to its corners
+	size := float64(scale) / 2
+	// cells in a column are stacked sqrt(3) * size apart and columns are 1.5 * size apart
+	rowHeight, colWidth := math.Sqrt(3)*size, 1.5*size
+
+	width = int(math.Ceil(colWidth*float64(g.width-1)+2*size)) + gutter*2
+	height = int(math.Ceil(rowHeight*(float64(g.height)+0.5))) + gutter*2
+
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			c := g.cells[row][col]
+
+			// derive the center of the cell in the image
+			cx := float64(gutter) + size + colWidth*float64(col)
+			cy := float64(gutter) + rowHeight/2 + rowHeight*float64(row)
+			if col%2 == 1 {
+				cy += rowHeight / 2
+			}
+
+			// the corner at angle 0 is due east, and the corners go clockwise since y increases downwards.
+			// the wall in direction dir runs from the corner at 240+60*dir degrees to the next corner.
+			corner := func(n int) point {
+				angle := float64(n%6) * math.Pi / 3
+				return point{x: cx + size*math.Cos(angle), y: cy + size*math.Sin(angle)}
+			}
+			for dir := hexNorth; dir <= hexNorthWest; dir++ {
+				if !c.walls[dir] {
+					continue
+				}
+				// the north, northeast, and southeast walls are drawn by this cell. the others are drawn by
+				// the neighbor on the other side, so this cell only draws them on the edge of the grid.
+				if dir <= hexSouthEast || c.neighbors[dir] == nil {
+					lines = append(lines, line{from: corner(4 + dir), to: corner(5 + dir)})
+				}
+			}
+		}
+	}
+
+	return height, width, lines
+}
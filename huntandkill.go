@@ -0,0 +1,57 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// RectangleHuntAndKill creates a maze using the hunt-and-kill algorithm.
+// it tends to make long, winding passages with fewer dead ends than Wilson's algorithm.
+func RectangleHuntAndKill(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, HuntAndKillGenerator{}, solve)
+}
+
+// carveHuntAndKill carves passages through the grid using the hunt-and-kill algorithm.
+// it walks at random into cells that aren't in the maze yet until it gets stuck.
+// then it hunts, scanning the grid row by row for a cell that isn't in the maze but has
+// a neighbor that is. that cell is linked to the neighbor and the walk starts again from it.
+// the maze is done when the hunt doesn't find a cell.
+func (g *grid) carveHuntAndKill(rng *rand.Rand) {
+	current := g.cells[rng.Intn(g.height)][rng.Intn(g.width)]
+	current.in = true
+	for current != nil {
+		// walk into a random neighbor that isn't in the maze yet
+		var unvisited []*cell
+		for _, neighbor := range current.neighborhood {
+			if !neighbor.in {
+				unvisited = append(unvisited, neighbor)
+			}
+		}
+		if len(unvisited) != 0 {
+			next := unvisited[rng.Intn(len(unvisited))]
+			current.linkTo(next)
+			next.in = true
+			current = next
+			continue
+		}
+
+		// we're stuck, so hunt for a cell to start the next walk from
+		current = nil
+		for _, c := range g.allCells() {
+			if c.in {
+				continue
+			}
+			var visited []*cell
+			for _, neighbor := range c.neighborhood {
+				if neighbor.in {
+					visited = append(visited, neighbor)
+				}
+			}
+			if len(visited) != 0 {
+				c.linkTo(visited[rng.Intn(len(visited))])
+				c.in = true
+				current = c
+				break
+			}
+		}
+	}
+}
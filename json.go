@@ -0,0 +1,198 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonMaze is the serialized form of a maze.
+type jsonMaze struct {
+	Height   int    `json:"height"`
+	Width    int    `json:"width"`
+	Entrance [2]int `json:"entrance"`
+	Exit     [2]int `json:"exit"`
+	// MoreEntrances and MoreExits hold any gates after the first ones
+	MoreEntrances [][2]int `json:"more_entrances,omitempty"`
+	MoreExits     [][2]int `json:"more_exits,omitempty"`
+	Solved        bool     `json:"solved,omitempty"`
+	Seed          int64    `json:"seed,omitempty"`
+	Algorithm     string   `json:"algorithm,omitempty"`
+	// Masked holds the cells that are not part of the maze's shape
+	Masked [][2]int `json:"masked,omitempty"`
+	// Crossings holds the cells of a weave maze that have a tunnel beneath them
+	Crossings [][2]int     `json:"crossings,omitempty"`
+	Cells     [][]jsonCell `json:"cells"`
+}
+
+// jsonCell holds the wall flags for a single cell.
+type jsonCell struct {
+	North bool `json:"north"`
+	East  bool `json:"east"`
+	South bool `json:"south"`
+	West  bool `json:"west"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// it writes the dimensions of the maze, the walls of every cell, and the coordinates of the entrance and exit,
+// along with the seed and algorithm that generated the maze when they are known.
+// the cells that a mask left out of the maze are listed so that the shape survives loading,
+// and so are the crossings of a weave maze.
+// any additional entrances and exits are written to separate lists so that older readers still see the first ones.
+func (r *Rectangle) MarshalJSON() ([]byte, error) {
+	jm := jsonMaze{
+		Height:    r.g.height,
+		Width:     r.g.width,
+		Entrance:  [2]int{r.entrances[0].row, r.entrances[0].col},
+		Exit:      [2]int{r.exits[0].row, r.exits[0].col},
+		Solved:    r.solved,
+		Seed:      r.seed,
+		Algorithm: r.algorithm,
+		Cells:     make([][]jsonCell, r.g.height),
+	}
+	for _, c := range r.entrances[1:] {
+		jm.MoreEntrances = append(jm.MoreEntrances, [2]int{c.row, c.col})
+	}
+	for _, c := range r.exits[1:] {
+		jm.MoreExits = append(jm.MoreExits, [2]int{c.row, c.col})
+	}
+	for row := 0; row < r.g.height; row++ {
+		jm.Cells[row] = make([]jsonCell, r.g.width)
+		for col := 0; col < r.g.width; col++ {
+			c := r.g.cells[row][col]
+			if c.masked {
+				jm.Masked = append(jm.Masked, [2]int{row, col})
+			}
+			if c.under {
+				jm.Crossings = append(jm.Crossings, [2]int{row, col})
+			}
+			jm.Cells[row][col] = jsonCell{
+				North: c.walls.north,
+				East:  c.walls.east,
+				South: c.walls.south,
+				West:  c.walls.west,
+			}
+		}
+	}
+	return json.Marshal(jm)
+}
+
+// LoadJSON creates a maze from data written by MarshalJSON.
+// if the maze was solved when it was written, it is solved again after loading.
+func LoadJSON(data []byte) (*Rectangle, error) {
+	var jm jsonMaze
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, err
+	}
+
+	// validate the dimensions before we allocate the grid
+	if err := validateDimensions(jm.Height, jm.Width); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	} else if len(jm.Cells) != jm.Height {
+		return nil, fmt.Errorf("json: want %d rows of cells, got %d", jm.Height, len(jm.Cells))
+	}
+	for row := range jm.Cells {
+		if len(jm.Cells[row]) != jm.Width {
+			return nil, fmt.Errorf("json: row %d: want %d cells, got %d", row, jm.Width, len(jm.Cells[row]))
+		}
+	}
+	entrances := append([][2]int{jm.Entrance}, jm.MoreEntrances...)
+	exits := append([][2]int{jm.Exit}, jm.MoreExits...)
+	for _, gate := range append(append([][2]int{}, entrances...), exits...) {
+		if gate[0] < 0 || gate[0] >= jm.Height || gate[1] < 0 || gate[1] >= jm.Width {
+			return nil, fmt.Errorf("json: gate (%d, %d) is out of bounds", gate[0], gate[1])
+		}
+	}
+
+	// restore the shape of the maze before copying the walls into a new grid.
+	// masked cells have no neighbors, so the walls facing them are outer walls.
+	g := createGrid(jm.Height, jm.Width)
+	if len(jm.Masked) != 0 {
+		mask := make([][]bool, jm.Height)
+		for row := range mask {
+			mask[row] = make([]bool, jm.Width)
+			for col := range mask[row] {
+				mask[row][col] = true
+			}
+		}
+		for _, rc := range jm.Masked {
+			if !g.inBounds(rc[0], rc[1]) {
+				return nil, fmt.Errorf("json: masked cell (%d, %d) is out of bounds", rc[0], rc[1])
+			}
+			mask[rc[0]][rc[1]] = false
+		}
+		if err := g.applyMask(mask); err != nil {
+			return nil, fmt.Errorf("json: %w", err)
+		}
+	}
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			c, jc := g.cells[row][col], jm.Cells[row][col]
+			c.walls.north, c.walls.east, c.walls.south, c.walls.west = jc.North, jc.East, jc.South, jc.West
+			c.in = !c.masked
+		}
+	}
+
+	for _, rc := range jm.Crossings {
+		c, ok := g.at(rc[0], rc[1])
+		if !ok {
+			return nil, fmt.Errorf("json: crossing (%d, %d) is out of bounds", rc[0], rc[1])
+		} else if len(c.neighborhood) != 4 {
+			return nil, fmt.Errorf("json: crossing (%d, %d) needs four neighbors", rc[0], rc[1])
+		}
+		c.under = true
+	}
+
+	// walls between neighbors must agree with each other. the only exception is a tunnel,
+	// where the wall of the crossing is closed and the wall of its neighbor is open.
+	agree := func(c *cell, cWall bool, n *cell, nWall bool) bool {
+		return cWall == nWall || (c.under && cWall && !nWall) || (n.under && nWall && !cWall)
+	}
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			c := g.cells[row][col]
+			if n := c.neighbors.east; n != nil && !agree(c, c.walls.east, n, n.walls.west) {
+				return nil, fmt.Errorf("json: cell (%d, %d): east wall does not match its neighbor", row, col)
+			}
+			if n := c.neighbors.south; n != nil && !agree(c, c.walls.south, n, n.walls.north) {
+				return nil, fmt.Errorf("json: cell (%d, %d): south wall does not match its neighbor", row, col)
+			}
+		}
+	}
+
+	r := &Rectangle{g: g, seed: jm.Seed, algorithm: jm.Algorithm}
+	for _, gate := range append(append([][2]int{}, entrances...), exits...) {
+		if g.cells[gate[0]][gate[1]].masked {
+			return nil, fmt.Errorf("json: gate (%d, %d) is masked", gate[0], gate[1])
+		}
+	}
+	for _, gate := range entrances {
+		c := g.cells[gate[0]][gate[1]]
+		c.entrance, c.gate = true, openSide(c)
+		r.entrances = append(r.entrances, c)
+	}
+	for _, gate := range exits {
+		c := g.cells[gate[0]][gate[1]]
+		c.exit, c.gate = true, openSide(c)
+		r.exits = append(r.exits, c)
+	}
+	if jm.Solved {
+		if err := r.Solve(); err != nil {
+			return nil, fmt.Errorf("json: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// openSide returns the side of a gate whose outer wall is open, so that the gate stays on that side
+// if the border is sealed later. the walls are saved but the sides of the gates are not.
+func openSide(c *cell) Direction {
+	for _, dir := range searchOrder {
+		if c.neighbor(dir) == nil && !c.wall(dir) {
+			return dir
+		}
+	}
+	return North
+}
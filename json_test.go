@@ -0,0 +1,68 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// renderText returns the text rendering of the maze.
+func renderText(t *testing.T, r *Rectangle) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := r.RenderText(&b); err != nil {
+		t.Fatalf("RenderText: %v", err)
+	}
+	return b.String()
+}
+
+// roundTripJSON marshals the maze and loads it back.
+func roundTripJSON(t *testing.T, r *Rectangle) *Rectangle {
+	t.Helper()
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	loaded, err := LoadJSON(data)
+	if err != nil {
+		t.Fatalf("LoadJSON: %v", err)
+	}
+	return loaded
+}
+
+func TestJSONMaskedRoundTrip(t *testing.T) {
+	r, err := RectangleMaskedMaze(plusMask(), false)
+	if err != nil {
+		t.Fatalf("RectangleMaskedMaze: %v", err)
+	}
+	loaded := roundTripJSON(t, r)
+	if want, got := renderText(t, r), renderText(t, loaded); got != want {
+		t.Errorf("RenderText: want\n%s\ngot\n%s", want, got)
+	}
+	for row := 0; row < 5; row++ {
+		for col := 0; col < 5; col++ {
+			if want, got := r.g.cells[row][col].masked, loaded.g.cells[row][col].masked; got != want {
+				t.Errorf("cell (%d, %d): masked: want %v, got %v", row, col, want, got)
+			}
+		}
+	}
+	if !loaded.IsPerfect() {
+		t.Errorf("IsPerfect: want true, got false")
+	}
+}
+
+func TestLoadJSONDimensions(t *testing.T) {
+	for _, data := range []string{
+		`{"height":1,"width":3,"entrance":[0,0],"exit":[0,2],"cells":[[{},{},{}]]}`,
+		`{"height":3,"width":1,"entrance":[0,0],"exit":[2,0],"cells":[[{}],[{}],[{}]]}`,
+		`{"height":0,"width":0,"entrance":[0,0],"exit":[0,0],"cells":[]}`,
+	} {
+		_, err := LoadJSON([]byte(data))
+		if err == nil || !strings.Contains(err.Error(), "invalid dimensions") {
+			t.Errorf("LoadJSON(%s): want invalid dimensions error, got %v", data, err)
+		}
+	}
+}
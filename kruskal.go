@@ -0,0 +1,39 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// RectangleKruskal creates a maze using randomized Kruskal's algorithm.
+func RectangleKruskal(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, KruskalGenerator{}, solve)
+}
+
+// carveKruskal carves passages through the grid using randomized Kruskal's algorithm.
+// every internal wall is visited in a random order and removed only if the cells on
+// either side of it are not already connected.
+func (g *grid) carveKruskal(rng *rand.Rand) {
+	// list every internal wall as the pair of cells that it separates
+	var walls [][2]*cell
+	for _, c := range g.allCells() {
+		if c.neighbors.east != nil {
+			walls = append(walls, [2]*cell{c, c.neighbors.east})
+		}
+		if c.neighbors.south != nil {
+			walls = append(walls, [2]*cell{c, c.neighbors.south})
+		}
+	}
+	rng.Shuffle(len(walls), func(i, j int) {
+		walls[i], walls[j] = walls[j], walls[i]
+	})
+
+	// each cell starts in its own set, identified by its row-major index
+	sets := newDisjointSet(g.height * g.width)
+	for _, wall := range walls {
+		a, b := wall[0], wall[1]
+		if sets.union(a.row*g.width+a.col, b.row*g.width+b.col) {
+			a.linkTo(b)
+			a.in, b.in = true, true
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "fmt"
+
+// RectangleMaskedMaze creates a maze shaped by the mask using Wilson's algorithm.
+// the dimensions of the maze are taken from the mask. cells are included in the maze
+// only if their mask value is true; the others are never carved and are not rendered.
+// the included cells must be connected to each other through their north, east, south, and west sides.
+// the entrance is on the northern wall of the first cell in the top row of the shape
+// and the exit is on the southern wall of the last cell in the bottom row.
+func RectangleMaskedMaze(mask [][]bool, solve bool) (*Rectangle, error) {
+	height := len(mask)
+	if height == 0 {
+		return nil, fmt.Errorf("mask: missing rows")
+	}
+	width := len(mask[0])
+	for row := range mask {
+		if len(mask[row]) != width {
+			return nil, fmt.Errorf("mask: row %d: want %d columns, got %d", row, width, len(mask[row]))
+		}
+	}
+	return RectangleMazeWith(height, width, WilsonGenerator{}, solve, withMask(mask))
+}
+
+// placeMaskedGates opens the entrance on the northern wall of the first unmasked cell, in row-major order,
+// and the exit on the southern wall of the last one. the cells next to those walls are either off the grid
+// or masked, so the walls are always outer walls.
+func placeMaskedGates(g *grid) (entrance, exit *cell) {
+	for _, c := range g.allCells() {
+		if c.masked {
+			continue
+		}
+		if entrance == nil {
+			entrance = c
+		}
+		exit = c
+	}
+	entrance.entrance = true
+	entrance.openGate(North)
+	exit.exit = true
+	exit.openGate(South)
+	return entrance, exit
+}
+
+// applyMask flags the cells that are off in the mask and unlinks them from their neighbors,
+// so that random walks never enter them. it returns an error if fewer than two cells are on
+// or if the cells that are on are not connected.
+func (g *grid) applyMask(mask [][]bool) error {
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			if mask[row][col] {
+				continue
+			}
+			c := g.cells[row][col]
+			c.masked = true
+			// remove the links in both directions
+			if n := c.neighbors.north; n != nil {
+				n.neighbors.south = nil
+			}
+			if n := c.neighbors.east; n != nil {
+				n.neighbors.west = nil
+			}
+			if n := c.neighbors.south; n != nil {
+				n.neighbors.north = nil
+			}
+			if n := c.neighbors.west; n != nil {
+				n.neighbors.east = nil
+			}
+			for _, n := range c.neighborhood {
+				for i, nn := range n.neighborhood {
+					if nn == c {
+						n.neighborhood = append(n.neighborhood[:i], n.neighborhood[i+1:]...)
+						break
+					}
+				}
+			}
+			c.neighbors.north, c.neighbors.east, c.neighbors.south, c.neighbors.west = nil, nil, nil, nil
+			c.neighborhood = nil
+		}
+	}
+
+	// count the unmasked cells and make sure they are all linked together
+	var start *cell
+	count := 0
+	for _, c := range g.allCells() {
+		if !c.masked {
+			if start == nil {
+				start = c
+			}
+			count++
+		}
+	}
+	if count < 2 {
+		return fmt.Errorf("mask: need at least 2 cells, got %d", count)
+	}
+	reached := map[*cell]bool{start: true}
+	queue := []*cell{start}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range current.neighborhood {
+			if !reached[neighbor] {
+				reached[neighbor] = true
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	if len(reached) != count {
+		return fmt.Errorf("mask: cells are not connected")
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+// plusMask returns a 5 x 5 mask shaped like a plus sign, with arms one cell wide.
+func plusMask() [][]bool {
+	mask := make([][]bool, 5)
+	for row := range mask {
+		mask[row] = make([]bool, 5)
+		for col := range mask[row] {
+			mask[row][col] = row == 2 || col == 2
+		}
+	}
+	return mask
+}
+
+func TestRectangleMaskedMaze(t *testing.T) {
+	mask := plusMask()
+	r, err := RectangleMaskedMaze(mask, true)
+	if err != nil {
+		t.Fatalf("RectangleMaskedMaze: %v", err)
+	}
+	if row, col := r.Entrance(); row != 0 || col != 2 {
+		t.Errorf("entrance: want (0, 2), got (%d, %d)", row, col)
+	}
+	if row, col := r.Exit(); row != 4 || col != 2 {
+		t.Errorf("exit: want (4, 2), got (%d, %d)", row, col)
+	}
+
+	distances := r.DistanceField()
+	for row := range mask {
+		for col := range mask[row] {
+			n, e, s, w := r.CellOpenings(row, col)
+			if !mask[row][col] {
+				// masked cells are never carved
+				if n || e || s || w {
+					t.Errorf("cell (%d, %d): masked cell has an opening", row, col)
+				}
+				if distances[row][col] != -1 {
+					t.Errorf("cell (%d, %d): masked cell is reachable", row, col)
+				}
+			} else if distances[row][col] == -1 {
+				t.Errorf("cell (%d, %d): cell is not reachable from the entrance", row, col)
+			}
+		}
+	}
+	if !r.IsPerfect() {
+		t.Errorf("IsPerfect: want true, got false")
+	}
+	if got := r.SolutionLength(); got != 5 {
+		t.Errorf("SolutionLength: want 5, got %d", got)
+	}
+}
+
+func TestRectangleMaskedMazeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		mask [][]bool
+	}{
+		{"empty", nil},
+		{"ragged", [][]bool{{true, true}, {true}}},
+		{"too small", [][]bool{{true}}},
+		{"one cell", [][]bool{{true, false}, {false, false}}},
+		{"disconnected", [][]bool{{true, false}, {false, true}}},
+	} {
+		if _, err := RectangleMaskedMaze(tc.mask, false); err == nil {
+			t.Errorf("%s: want error, got nil", tc.name)
+		}
+	}
+}
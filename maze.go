@@ -4,241 +4,541 @@
 package maze
 
 import (
-	"log"
+	"context"
+	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 )
 
+// Logger receives the progress messages that the package writes while mazes are generated and solved.
+// *log.Logger and *slog.Logger (through slog.NewLogLogger) both satisfy it.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// logger is the destination for progress messages. it is nil by default,
+// so the package is quiet when used as a library or benchmarked.
+var logger Logger
+
+// SetLogger routes the package's progress messages to l. passing nil turns the messages off.
+// it should be called before generating mazes, since the logger is not protected by a lock.
+func SetLogger(l Logger) {
+	logger = l
+}
+
+// logf writes a progress message to the logger, if there is one.
+func logf(format string, v ...any) {
+	if logger != nil {
+		logger.Printf(format, v...)
+	}
+}
+
 type Rectangle struct {
-	g        *grid
-	entrance *cell
-	exit     *cell
-	solved   bool
+	// mu guards solving, so that concurrent calls to Solve don't race on the cell flags
+	mu sync.Mutex
+	g  *grid
+	// entrances and exits are the gates of the maze. there is always at least one of each,
+	// and the first of each is the one reported by Entrance and Exit.
+	entrances []*cell
+	exits     []*cell
+	solved    bool
+	// seed and algorithm describe how the maze was generated
+	seed      int64
+	algorithm string
+	// length is the number of cells on the solution path, cached by SolutionLength
+	length int
+	// trace is the order that the last call to Solve explored the cells in
+	trace []*cell
+	// steps is the order that cells were carved, if it was recorded during generation
+	steps []carveStep
+}
+
+// CellInfo identifies a single cell in the maze.
+type CellInfo struct {
+	Row, Col int
 }
 
+// RectangleMaze creates a maze using Wilson's algorithm.
+// the maze's seed is taken from the global source, so seeding the global source still makes the maze repeatable.
+// the same global seed does not give the same maze that it did before mazes were carved from their own source.
 func RectangleMaze(height, width int, solve bool) (*Rectangle, error) {
-	g := createGrid(height, width)
-
-	// create a stack containing all the cells in the grid in a random order
-	var stack []*cell
-	stack = g.allCells()
-	rand.Shuffle(len(stack), func(i, j int) {
-		stack[i], stack[j] = stack[j], stack[i]
-	})
-
-	// randomly add a cell to the maze.
-	// since the stack contains all cells in a random order, we can just pop the first cell from it
-	// and mark it as in.
-	stack[0].in = true
-	stack = stack[1:]
-
-	// while the stack is not empty, pop a cell.
-	// perform a random walk from that cell, stopping only when we encounter a cell that is already in the maze.
-	// for every cell that we visit, we record the direction that we exited so that we'll be able to retrace our path.
-	for len(stack) != 0 {
-		// pick a cell at random from the stack.
-		// since the stack is randomly shuffled before we start, we can just pop the first cell.
-		from := stack[0]
-		if from == nil {
-			panic("assert(from != nil)")
-		}
-		stack = stack[1:]
+	// the seed is taken from the global source so that callers can still seed it
+	return RectangleMazeWith(height, width, WilsonGenerator{}, solve)
+}
+
+// RectangleMazeContext creates a maze like RectangleMaze, but stops and returns the context's error
+// if the context is cancelled while the maze is being carved.
+func RectangleMazeContext(ctx context.Context, height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, WilsonGenerator{}, solve, WithContext(ctx))
+}
+
+// RectangleMazeProgress creates a maze like RectangleMaze, calling progress each time a cell is added to the maze.
+// done is the number of cells that have been added so far and total is the number of cells in the maze.
+// progress may be nil.
+func RectangleMazeProgress(height, width int, solve bool, progress func(done, total int)) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, WilsonGenerator{}, solve, WithProgress(progress))
+}
+
+// GenerateIntGrid creates a maze from the seed and returns it as an integer grid (0 = path, 1 = wall)
+// along with the grid coordinates of the entrance and exit openings in the outer wall.
+// the result is in the format expected by the reachability check in cmd/solver.
+// it returns nil values if the maze can't be created.
+func GenerateIntGrid(height, width int, seed int64) ([][]int, [][2]int) {
+	r, err := RectangleMazeWithSeed(height, width, WilsonGenerator{}, seed, false)
+	if err != nil {
+		return nil, nil
+	}
+	// the openings are in the outer wall, directly north of the entrance and directly south of the exit
+	entranceRow, entranceCol := r.Entrance()
+	exitRow, exitCol := r.Exit()
+	exits := [][2]int{
+		{entranceRow * 2, entranceCol*2 + 1},
+		{exitRow*2 + 2, exitCol*2 + 1},
+	}
+	return r.g.toIntGrid(), exits
+}
+
+// Seed returns the seed of the random source that the maze was generated from.
+// passing it to RectangleMazeWithSeed, along with the generator for Algorithm and the same
+// dimensions, creates the same maze. it is 0 if the seed isn't known, like for mazes that
+// were carved by hand or generated from a caller's source.
+func (r *Rectangle) Seed() int64 {
+	return r.seed
+}
+
+// Algorithm returns the name of the algorithm that carved the maze, like "wilson" or "kruskal".
+// it is empty if the maze was carved by hand.
+func (r *Rectangle) Algorithm() string {
+	return r.algorithm
+}
+
+// Entrance returns the row and column of the entrance cell.
+// if the maze has more than one entrance, it returns the first one.
+func (r *Rectangle) Entrance() (row, col int) {
+	return r.entrances[0].row, r.entrances[0].col
+}
+
+// Exit returns the row and column of the exit cell.
+// if the maze has more than one exit, it returns the first one.
+func (r *Rectangle) Exit() (row, col int) {
+	return r.exits[0].row, r.exits[0].col
+}
+
+// Dimensions returns the number of rows and columns in the maze.
+func (r *Rectangle) Dimensions() (height, width int) {
+	return r.g.height, r.g.width
+}
+
+// InBounds returns true if the row and column are inside the maze.
+func (r *Rectangle) InBounds(row, col int) bool {
+	return r.g.inBounds(row, col)
+}
+
+// CellOpenings reports which sides of the cell are open.
+// the entrance and exit are open on the outer wall.
+// it returns false for every side if the cell is out of bounds.
+func (r *Rectangle) CellOpenings(row, col int) (north, east, south, west bool) {
+	c, ok := r.g.at(row, col)
+	if !ok {
+		return false, false, false, false
+	}
+	return !c.walls.north, !c.walls.east, !c.walls.south, !c.walls.west
+}
 
-		// clear the walk pointers for this iteration
-		g.clearWalk()
+// ForEachCell calls fn for every cell in row-major order, reporting which sides of the cell are open
+// the same way CellOpenings does.
+func (r *Rectangle) ForEachCell(fn func(row, col int, n, e, s, w bool)) {
+	for row := 0; row < r.g.height; row++ {
+		for col := 0; col < r.g.width; col++ {
+			c := r.g.cells[row][col]
+			fn(row, col, !c.walls.north, !c.walls.east, !c.walls.south, !c.walls.west)
+		}
+	}
+}
 
-		// randomly walk until we find a cell that is already in the maze
-		for to := from; !to.in; {
-			// pick a neighboring cell at random
-			to.to = to.randomNeighbor()
-			// and move to it
-			to = to.to
+// OpeningsMask returns the open sides of every cell packed into the low four bits of a byte,
+// indexed by row and then column. the bit for a side is 1 << its Direction, so north is 1, east is 2,
+// south is 4, and west is 8. like CellOpenings, the entrance and exit are open on the outer wall.
+func (r *Rectangle) OpeningsMask() [][]uint8 {
+	mask := make([][]uint8, r.g.height)
+	for row := range mask {
+		mask[row] = make([]uint8, r.g.width)
+		for col := range mask[row] {
+			c := r.g.cells[row][col]
+			for _, dir := range []Direction{North, East, South, West} {
+				if !c.wall(dir) {
+					mask[row][col] |= 1 << dir
+				}
+			}
 		}
+	}
+	return mask
+}
+
+// OpenWall removes the wall on the given side of the cell, along with the matching wall on its neighbor.
+// it returns an error if the cell is out of bounds or there is no neighbor in that direction.
+func (r *Rectangle) OpenWall(row, col int, dir Direction) error {
+	c, ok := r.g.at(row, col)
+	if !ok {
+		return fmt.Errorf("cell (%d, %d) is out of bounds", row, col)
+	}
+	neighbor := c.neighbor(dir)
+	if neighbor == nil {
+		return fmt.Errorf("cell (%d, %d) has no neighbor to the %s", row, col, dir)
+	}
+	c.linkTo(neighbor)
+	return nil
+}
+
+// ToggleWall opens the wall on the given side of the cell if it is closed and closes it if it is open,
+// changing the matching wall on its neighbor too. it clears any solution, since the path may have changed;
+// use IsPerfect to check the maze after editing it.
+// it returns an error if the cell is out of bounds or there is no neighbor in that direction.
+func (r *Rectangle) ToggleWall(row, col int, dir Direction) error {
+	c, ok := r.g.at(row, col)
+	if !ok {
+		return fmt.Errorf("cell (%d, %d) is out of bounds", row, col)
+	}
+	neighbor := c.neighbor(dir)
+	if neighbor == nil {
+		return fmt.Errorf("cell (%d, %d) has no neighbor to the %s", row, col, dir)
+	}
+	if c.wall(dir) {
+		c.linkTo(neighbor)
+	} else {
+		c.unlinkFrom(neighbor)
+	}
+	r.ResetSolution()
+	return nil
+}
+
+// carveWilson carves passages through the grid using Wilson's algorithm.
+// cells that are already in the maze are left as is; if there are none, a random cell is added first.
+func (g *grid) carveWilson(rng *rand.Rand) {
+	_ = g.carveWilsonContext(context.Background(), rng)
+}
 
-		// retrace the walk, removing walls as needed, until we find a cell that is in the maze
-		for !from.in {
-			to := from.to
-			// remove the wall between the from and to cells
-			if from.neighbors.north == to {
-				from.walls.north = false
-				to.walls.south = false
-			} else if from.neighbors.east == to {
-				from.walls.east = false
-				to.walls.west = false
-			} else if from.neighbors.south == to {
-				from.walls.south = false
-				to.walls.north = false
-			} else if from.neighbors.west == to {
-				from.walls.west = false
-				to.walls.east = false
+// carveWilsonContext implements carveWilson, checking the context periodically
+// and returning its error if it has been cancelled.
+func (g *grid) carveWilsonContext(ctx context.Context, rng *rand.Rand) error {
+	// masked cells are never added to the maze, so they are left out of the walks
+	var cells []*cell
+	for _, c := range g.allCells() {
+		if !c.masked {
+			cells = append(cells, c)
+		}
+	}
+	return wilsonGraph[*cell]{
+		cells: cells,
+		in:    func(c *cell) bool { return c.in },
+		add: func(from, to *cell) {
+			if to != nil {
+				from.linkTo(to)
 			}
-			// the cell is now in the maze, so mark it
 			from.in = true
-			// walk to the next cell
-			from = from.to
-		}
+			if g.onCarve != nil {
+				g.onCarve(from, to)
+			}
+		},
+		neighbor: func(c *cell) *cell { return c.randomNeighbor(rng, g.bias) },
+		next:     func(c *cell) *cell { return c.to },
+		setNext:  func(c, next *cell) { c.to = next },
+	}.carve(ctx, rng)
+}
+
+// RectangleFromGrid wraps a hand-carved grid in a Rectangle, placing the entrance and exit like the generators do.
+// the rectangle gets a copy of the grid's cells, so carving the grid later doesn't change the maze,
+// and wrapping the same grid again creates a separate maze with its own gates.
+// if solve is set and there is no path from the entrance to the exit, the maze is left unsolved.
+// it returns nil if the grid is nil.
+func RectangleFromGrid(g *Grid, solve bool) *Rectangle {
+	if g == nil {
+		return nil
+	}
+	cells := g.g.clone()
+	entrance, exit := placeGates(cells, rand.New(rand.NewSource(rand.Int63())))
+	r := &Rectangle{
+		g:         cells,
+		entrances: []*cell{entrance},
+		exits:     []*cell{exit},
+	}
+	if solve {
+		_ = r.Solve()
 	}
+	return r
+}
+
+// gateSource returns a new source for placing the gates, seeded from rng.
+// it must be called before rng is used for carving. that way the gates depend only on the seed,
+// so mazes carved by different algorithms from the same seed share the same entrance and exit.
+func gateSource(rng *rand.Rand) *rand.Rand {
+	return rand.New(rand.NewSource(rng.Int63()))
+}
+
+// placeGates randomly assigns an entrance on the northern edge and an exit on the southern edge of the grid.
+// rng should be independent of the source used to carve the maze; see gateSource.
+func placeGates(g *grid, rng *rand.Rand) (entrance, exit *cell) {
+	// narrow mazes still need a window of at least one cell.
+	theGate := max(1, g.width/6)
+	return placeGatesWithin(g, rng, theGate, theGate)
+}
 
+// placeGatesWithin assigns an entrance on the northern edge and an exit on the southern edge of the grid.
+// the entrance is picked from the first entranceWindow cells at the western end of its edge and the exit
+// from the last exitWindow cells at the eastern end of its edge. both windows must be at least 1.
+func placeGatesWithin(g *grid, rng *rand.Rand, entranceWindow, exitWindow int) (entrance, exit *cell) {
 	// define constants for the edges of the maze
 	north, east, south, west := 0, g.width-1, g.height-1, 0
 
-	// randomly assign an entrance and exit to the maze.
-	// entrances and exits will be on the western and eastern sides of the maze.
-	theGate := g.width / 6
-	// the entrance will be on the western third of the northern edge of the maze.
+	// the entrance will be on the western part of the northern edge of the maze.
 	entranceRow, entranceCol := north, west
-	entranceCol = west + rand.Intn(theGate)
-	// the exit will be on the eastern third of the southern edge of the maze.
+	entranceCol = west + rng.Intn(entranceWindow)
+	// the exit will be on the eastern part of the southern edge of the maze.
 	exitRow, exitCol := south, east
-	exitCol = east - rand.Intn(theGate)
+	exitCol = east - rng.Intn(exitWindow)
 	// set the flags on the entrance and exit cells
-	entrance := g.cells[entranceRow][entranceCol]
+	entrance = g.cells[entranceRow][entranceCol]
 	entrance.entrance = true
-	entrance.walls.north = false
-	exit := g.cells[exitRow][exitCol]
+	entrance.openGate(North)
+	exit = g.cells[exitRow][exitCol]
 	exit.exit = true
-	exit.walls.south = false
-
-	if solve {
-		started := time.Now()
-		log.Printf("maze: solving maze\n")
-
-		// clear the walk pointers for this search
-		g.clearWalk()
-
-		// solve the maze using depth-first search
-		stack = []*cell{entrance}
-		entrance.visited = true
-		for !stack[len(stack)-1].isExit() {
-			// pop current cell off top of stack
-			current := stack[len(stack)-1]
-			stack = stack[:len(stack)-1]
-
-			//log.Printf("maze: depth %6d current %4d %4d\n", len(stack), current.row, current.col)
-
-			// optimization - if neighbor is the exit, push it and quit searching
-			if current.southIsOpen() {
-				if neighbor := current.neighbors.south; neighbor.isExit() {
-					neighbor.visited = true
-					neighbor.to = current
-					stack = append(stack, neighbor)
-					break
-				}
-			}
-
-			// push all neighbors that haven't yet been visited on to the stack
-			if current.northIsOpen() {
-				if neighbor := current.neighbors.north; !neighbor.hasBeenVisited() {
-					neighbor.visited = true
-					neighbor.to = current
-					stack = append(stack, neighbor)
-				}
-			}
-			if current.eastIsOpen() {
-				if neighbor := current.neighbors.east; !neighbor.hasBeenVisited() {
-					neighbor.visited = true
-					neighbor.to = current
-					stack = append(stack, neighbor)
-				}
-			}
-			if current.southIsOpen() {
-				if neighbor := current.neighbors.south; !neighbor.hasBeenVisited() {
-					neighbor.visited = true
-					neighbor.to = current
-					stack = append(stack, neighbor)
-				}
-			}
-			if current.westIsOpen() {
-				if neighbor := current.neighbors.west; !neighbor.hasBeenVisited() {
-					neighbor.visited = true
-					neighbor.to = current
-					stack = append(stack, neighbor)
-				}
-			}
-		}
-		log.Printf("maze: solved  %5d x %5d maze in %v\n", g.height, g.width, time.Now().Sub(started))
+	exit.openGate(South)
 
-		// flag each cell that is on the path between the entrance and the exit
-		for c := exit; c != nil; c = c.to {
-			c.onPath = true
-		}
-	}
+	return entrance, exit
+}
 
-	return &Rectangle{
-		g:        g,
-		entrance: entrance,
-		exit:     exit,
-	}, nil
+// Solve finds a path from the entrance to the exit and flags the cells on it so that the renderers will show them.
+// it returns an error if there is no path, which can happen if walls have been added or the maze is masked.
+// solving a maze that has already been solved does nothing.
+// the neighbors of each cell are searched in a fixed order, so solving the same maze always gives the same path.
+//
+// Solve is safe to call from several goroutines at once; the first call solves the maze and the
+// others wait for it to finish. SolveBFS, SolveAStar, SolveDijkstra, and SolutionLength take the
+// same lock, so they can be mixed with Solve. once the maze is solved, the renderers only read it,
+// so they can run concurrently too, but not while a solver is replacing the solution.
+// ResetSolution and the wall and gate editors are not safe to call concurrently with anything.
+func (r *Rectangle) Solve() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.solve()
 }
 
-func (r *Rectangle) Solve() {
+// solve implements Solve. the caller must hold the lock.
+func (r *Rectangle) solve() error {
 	if r.solved {
-		return
+		return nil
 	}
 	started := time.Now()
-	log.Printf("maze: solving maze\n")
+	logf("maze: solving maze\n")
 
-	// clear the walk pointers for this search
-	r.g.clearWalk()
+	// clear the flags left by any earlier search that failed, and the trace
+	r.g.clearSolution()
+	r.trace = nil
+
+	// with several gates, the path must be the shortest one between any entrance and any exit
+	if len(r.entrances) > 1 || len(r.exits) > 1 {
+		var exit *cell
+		if exit, r.trace = r.g.solveShortest(r.entrances); exit == nil {
+			return fmt.Errorf("solve: no path from an entrance to an exit")
+		}
+		r.g.markPath(exit)
+		r.solved = true
+		return nil
+	}
 
 	// solve the maze using depth-first search
-	stack := []*cell{r.entrance}
-	r.entrance.visited = true
-	for !stack[len(stack)-1].isExit() {
-		// pop current cell off top of stack
+	stack := []*cell{r.entrances[0]}
+	r.entrances[0].visited = true
+	for len(stack) != 0 && !stack[len(stack)-1].isExit() {
+		// pop current cell off top of stack and record it in the trace
 		current := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
+		r.trace = append(r.trace, current)
 
-		//log.Printf("maze: depth %6d current %4d %4d\n", len(stack), current.row, current.col)
+		//logf("maze: depth %6d current %4d %4d\n", len(stack), current.row, current.col)
 
-		// optimization - if neighbor is the exit, push it and quit searching
-		if current.southIsOpen() {
-			if neighbor := current.neighbors.south; neighbor.isExit() {
-				neighbor.visited = true
-				neighbor.to = current
-				stack = append(stack, neighbor)
+		// push all neighbors that haven't yet been visited on to the stack.
+		// step follows tunnels, so a neighbor may be on the far side of a crossing.
+		// a cell is flagged as visited when it is pushed, so it can never be pushed twice.
+		// if the neighbor is the exit, stop pushing; it is on top of the stack, which ends the search.
+		for _, dir := range searchOrder {
+			neighbor := current.step(dir)
+			if neighbor == nil || neighbor.hasBeenVisited() {
+				continue
+			}
+			neighbor.visited = true
+			neighbor.to = current
+			stack = append(stack, neighbor)
+			if neighbor.isExit() {
 				break
 			}
 		}
+	}
+	// if the stack is empty, every reachable cell was explored without finding the exit
+	if len(stack) == 0 {
+		return fmt.Errorf("solve: no path from the entrance to the exit")
+	}
+	logf("maze: solved  %5d x %5d maze in %v\n", r.g.height, r.g.width, time.Now().Sub(started))
 
-		// push all neighbors that haven't yet been visited on to the stack
-		if current.northIsOpen() {
-			if neighbor := current.neighbors.north; !neighbor.hasBeenVisited() {
-				neighbor.visited = true
-				neighbor.to = current
-				stack = append(stack, neighbor)
-			}
+	// the search ends when the exit is on top of the stack, so it is the last cell explored
+	r.trace = append(r.trace, stack[len(stack)-1])
+
+	// flag each cell that is on the path between the entrance and the exit
+	r.g.markPath(stack[len(stack)-1])
+
+	r.solved = true
+	return nil
+}
+
+// SolveBFS finds the shortest path from the entrance to the exit using breadth-first search.
+// Solve uses depth-first search, which can find a long way around in a maze with loops; this always finds
+// a path with the fewest steps. if there are several gates, it finds the shortest path between any of them.
+// like SolveAStar, it always replaces any existing solution.
+// it returns an error if there is no path from an entrance to an exit.
+func (r *Rectangle) SolveBFS() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ResetSolution()
+	exit, _ := r.g.solveShortest(r.entrances)
+	if exit == nil {
+		return fmt.Errorf("solve: no path from an entrance to an exit")
+	}
+	r.g.markPath(exit)
+	r.solved = true
+	return nil
+}
+
+// solveShortest runs a breadth-first search from all the entrances at once, so the first exit
+// that it reaches is the one closest to any entrance. it returns that exit, or nil if there isn't one,
+// along with the cells in the order that they were explored.
+func (g *grid) solveShortest(entrances []*cell) (exit *cell, trace []*cell) {
+	queue := append([]*cell{}, entrances...)
+	for _, c := range entrances {
+		c.visited = true
+	}
+	for len(queue) != 0 {
+		// pop the first cell from the queue
+		current := queue[0]
+		queue = queue[1:]
+		trace = append(trace, current)
+		if current.isExit() {
+			return current, trace
 		}
-		if current.eastIsOpen() {
-			if neighbor := current.neighbors.east; !neighbor.hasBeenVisited() {
+
+		// push all open neighbors that haven't been visited yet
+		for _, neighbor := range current.openNeighbors() {
+			if !neighbor.hasBeenVisited() {
 				neighbor.visited = true
 				neighbor.to = current
-				stack = append(stack, neighbor)
+				queue = append(queue, neighbor)
 			}
 		}
-		if current.southIsOpen() {
-			if neighbor := current.neighbors.south; !neighbor.hasBeenVisited() {
-				neighbor.visited = true
-				neighbor.to = current
-				stack = append(stack, neighbor)
-			}
+	}
+	return nil, trace
+}
+
+// markPath flags each cell on the path that ends at the given cell, following the walk pointers back to the start.
+func (g *grid) markPath(end *cell) {
+	for c := end; c != nil; c = c.to {
+		c.onPath = true
+	}
+}
+
+// SolutionPath returns the coordinates of the cells on the solution path, in order from the entrance to the exit.
+// it returns nil if the maze hasn't been solved or if there is no path.
+func (r *Rectangle) SolutionPath() [][2]int {
+	if !r.solved {
+		return nil
+	}
+	// the path ends at the exit that the solver reached
+	var exit *cell
+	for _, c := range r.exits {
+		if c.onPath {
+			exit = c
+			break
 		}
-		if current.westIsOpen() {
-			if neighbor := current.neighbors.west; !neighbor.hasBeenVisited() {
-				neighbor.visited = true
-				neighbor.to = current
-				stack = append(stack, neighbor)
+	}
+	if exit == nil {
+		return nil
+	}
+	// walk back from the exit, then reverse the path so that it starts at the entrance
+	var path [][2]int
+	for c := exit; c != nil; c = c.to {
+		path = append(path, [2]int{c.row, c.col})
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// ValidatePath checks a path drawn through the maze, given as the coordinates of its cells in order.
+// the path must start at an entrance, end at an exit, and only move between neighboring cells
+// that have an open passage between them. it returns an error describing the first problem it finds.
+func (r *Rectangle) ValidatePath(path [][2]int) error {
+	if len(path) == 0 {
+		return fmt.Errorf("path: path is empty")
+	}
+	var prev *cell
+	for n, rc := range path {
+		c, ok := r.g.at(rc[0], rc[1])
+		if !ok {
+			return fmt.Errorf("path: step %d: cell (%d, %d) is out of bounds", n, rc[0], rc[1])
+		}
+		if prev == nil {
+			if !c.isEntrance() {
+				return fmt.Errorf("path: cell (%d, %d) is not an entrance", c.row, c.col)
 			}
+		} else if !prev.isOpenTo(c) {
+			if prev.neighbor(prev.directionTo(c)) != c {
+				return fmt.Errorf("path: step %d: cell (%d, %d) is not next to cell (%d, %d)", n, c.row, c.col, prev.row, prev.col)
+			}
+			return fmt.Errorf("path: step %d: a wall blocks the way from cell (%d, %d) to cell (%d, %d)", n, prev.row, prev.col, c.row, c.col)
 		}
+		prev = c
+	}
+	if !prev.isExit() {
+		return fmt.Errorf("path: cell (%d, %d) is not an exit", prev.row, prev.col)
 	}
-	log.Printf("maze: solved  %5d x %5d maze in %v\n", r.g.height, r.g.width, time.Now().Sub(started))
+	return nil
+}
 
-	// flag each cell that is on the path between the entrance and the exit
-	for c := r.exit; c != nil; c = c.to {
-		c.onPath = true
+// SolutionLength returns the number of cells on the path from the entrance to the exit, including both of them.
+// it solves the maze first if it hasn't been solved, and returns 0 if there is no path.
+// the length is cached, so calling it again is cheap until the solution is reset.
+// like Solve, it is safe to call from several goroutines at once.
+func (r *Rectangle) SolutionLength() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.solved {
+		if err := r.solve(); err != nil {
+			return 0
+		}
 	}
+	if r.length == 0 {
+		r.length = len(r.SolutionPath())
+	}
+	return r.length
+}
 
-	r.solved = true
+// ResetSolution clears the solution and the flags left by the solver, so that the next call to Solve
+// searches the maze again. use it after changing walls by hand; the methods that move the gates reset it for you.
+func (r *Rectangle) ResetSolution() {
+	r.g.clearSolution()
+	r.solved, r.length, r.trace = false, 0, nil
+}
+
+// SolveTrace returns the coordinates of the cells in the order that Solve explored them,
+// starting with the entrance. the trace includes the dead ends that the search backed out of,
+// so it shows how the solver worked rather than just the path it found.
+// it returns nil if the maze hasn't been solved with Solve, or if it has changed since then.
+func (r *Rectangle) SolveTrace() [][2]int {
+	var trace [][2]int
+	for _, c := range r.trace {
+		trace = append(trace, [2]int{c.row, c.col})
+	}
+	return trace
 }
 
 func SquareMaze(height int, solve bool) (*Rectangle, error) {
@@ -0,0 +1,42 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+// benchmarkRectangleMaze generates a seeded maze of the given size on every iteration,
+// solving it too if solve is set.
+func benchmarkRectangleMaze(b *testing.B, size int, solve bool) {
+	for i := 0; i < b.N; i++ {
+		if _, err := RectangleMazeWith(size, size, WilsonGenerator{}, solve, WithSeed(int64(i))); err != nil {
+			b.Fatalf("RectangleMazeWith: %v", err)
+		}
+	}
+}
+
+// benchmarkSolve solves the same seeded maze of the given size on every iteration.
+func benchmarkSolve(b *testing.B, size int) {
+	r, err := RectangleMazeWith(size, size, WilsonGenerator{}, false, WithSeed(1))
+	if err != nil {
+		b.Fatalf("RectangleMazeWith: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.ResetSolution()
+		if err := r.Solve(); err != nil {
+			b.Fatalf("Solve: %v", err)
+		}
+	}
+}
+
+func BenchmarkRectangleMaze50(b *testing.B)  { benchmarkRectangleMaze(b, 50, false) }
+func BenchmarkRectangleMaze125(b *testing.B) { benchmarkRectangleMaze(b, 125, false) }
+func BenchmarkRectangleMaze500(b *testing.B) { benchmarkRectangleMaze(b, 500, false) }
+
+func BenchmarkRectangleMazeSolved50(b *testing.B)  { benchmarkRectangleMaze(b, 50, true) }
+func BenchmarkRectangleMazeSolved125(b *testing.B) { benchmarkRectangleMaze(b, 125, true) }
+func BenchmarkRectangleMazeSolved500(b *testing.B) { benchmarkRectangleMaze(b, 500, true) }
+
+func BenchmarkSolve50(b *testing.B)  { benchmarkSolve(b, 50) }
+func BenchmarkSolve125(b *testing.B) { benchmarkSolve(b, 125) }
+func BenchmarkSolve500(b *testing.B) { benchmarkSolve(b, 500) }
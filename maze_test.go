@@ -0,0 +1,446 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+// passage is a pair of neighboring cells with an open wall between them.
+type passage [2][2]int
+
+// testMaze builds a maze by hand. every wall starts closed, the passages are carved,
+// and the gates are set with SetEntrance and SetExit.
+func testMaze(t *testing.T, height, width int, entrance, exit [2]int, passages ...passage) *Rectangle {
+	t.Helper()
+	g := createGrid(height, width)
+	for _, p := range passages {
+		a, b := g.cells[p[0][0]][p[0][1]], g.cells[p[1][0]][p[1][1]]
+		if !a.linkTo(b) {
+			t.Fatalf("testMaze: cells %v and %v are not neighbors", p[0], p[1])
+		}
+		a.in, b.in = true, true
+	}
+	r := &Rectangle{g: g}
+	if err := r.SetEntrance(entrance[0], entrance[1]); err != nil {
+		t.Fatalf("testMaze: %v", err)
+	} else if err = r.SetExit(exit[0], exit[1]); err != nil {
+		t.Fatalf("testMaze: %v", err)
+	}
+	return r
+}
+
+// loopMaze returns a 3 x 3 maze with the entrance at the north-west corner and the exit at the north-east corner.
+// the short way is straight along the northern row. the long way goes down the western column, along
+// the southern row, and back up the eastern column. depth-first search takes the long way.
+func loopMaze(t *testing.T) *Rectangle {
+	t.Helper()
+	return testMaze(t, 3, 3, [2]int{0, 0}, [2]int{0, 2},
+		passage{{0, 0}, {0, 1}}, passage{{0, 1}, {0, 2}},
+		passage{{0, 0}, {1, 0}}, passage{{1, 0}, {2, 0}}, passage{{2, 0}, {2, 1}},
+		passage{{2, 1}, {2, 2}}, passage{{2, 2}, {1, 2}}, passage{{1, 2}, {0, 2}},
+		passage{{1, 0}, {1, 1}},
+	)
+}
+
+func TestSolveBFS(t *testing.T) {
+	r := loopMaze(t)
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	dfs := len(r.SolutionPath())
+
+	if err := r.SolveBFS(); err != nil {
+		t.Fatalf("SolveBFS: %v", err)
+	}
+	bfs := r.SolutionPath()
+	if len(bfs) != 3 {
+		t.Errorf("SolveBFS: path length: want 3, got %d: %v", len(bfs), bfs)
+	}
+	if dfs <= len(bfs) {
+		t.Errorf("Solve: path length: want more than %d, got %d", len(bfs), dfs)
+	}
+}
+
+func TestSolveBFSNoPath(t *testing.T) {
+	// the exit is walled off from the rest of the maze
+	r := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 1},
+		passage{{0, 0}, {0, 1}}, passage{{0, 0}, {1, 0}},
+	)
+	if err := r.SolveBFS(); err == nil {
+		t.Fatalf("SolveBFS: want error, got nil")
+	}
+	if path := r.SolutionPath(); path != nil {
+		t.Errorf("SolutionPath: want nil, got %v", path)
+	}
+}
+
+func TestSolutionLength(t *testing.T) {
+	r := loopMaze(t)
+	// SolutionLength solves the maze with depth-first search, which takes the long way around
+	if got := r.SolutionLength(); got != 7 {
+		t.Errorf("SolutionLength: want 7, got %d", got)
+	}
+
+	// every solver must replace the cached length along with the path
+	for _, tc := range []struct {
+		name  string
+		solve func() error
+		want  int
+	}{
+		{"SolveBFS", r.SolveBFS, 3},
+		{"SolveAStar", r.SolveAStar, 3},
+		{"SolveDijkstra", r.SolveDijkstra, 3},
+	} {
+		if err := tc.solve(); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got, path := r.SolutionLength(), r.SolutionPath(); got != tc.want || got != len(path) {
+			t.Errorf("%s: SolutionLength: want %d, got %d with path %v", tc.name, tc.want, got, path)
+		}
+	}
+
+	r.ResetSolution()
+	if got := r.SolutionLength(); got != 7 {
+		t.Errorf("ResetSolution: SolutionLength: want 7, got %d", got)
+	}
+}
+
+func TestSolversNoPath(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		solve func(r *Rectangle) error
+	}{
+		{"SolveAStar", (*Rectangle).SolveAStar},
+		{"SolveDijkstra", (*Rectangle).SolveDijkstra},
+	} {
+		r := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 1},
+			passage{{0, 0}, {0, 1}}, passage{{0, 0}, {1, 0}},
+		)
+		if err := tc.solve(r); err == nil {
+			t.Errorf("%s: want error, got nil", tc.name)
+		}
+		if got := r.SolutionLength(); got != 0 {
+			t.Errorf("%s: SolutionLength: want 0, got %d", tc.name, got)
+		}
+	}
+}
+
+func TestGenerateIntGridSeed(t *testing.T) {
+	same := func(a, b [][]int) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for row := range a {
+			for col := range a[row] {
+				if a[row][col] != b[row][col] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	grid, exits := GenerateIntGrid(10, 12, 7)
+	if len(grid) != 21 || len(grid[0]) != 25 {
+		t.Fatalf("GenerateIntGrid: want 21 x 25, got %d x %d", len(grid), len(grid[0]))
+	}
+	if exits[0][0] != 0 || exits[1][0] != 20 {
+		t.Errorf("GenerateIntGrid: want exits on the first and last rows, got %v", exits)
+	}
+	again, againExits := GenerateIntGrid(10, 12, 7)
+	if !same(grid, again) || exits[0] != againExits[0] || exits[1] != againExits[1] {
+		t.Errorf("GenerateIntGrid: same seed gave different mazes")
+	}
+	if other, _ := GenerateIntGrid(10, 12, 8); same(grid, other) {
+		t.Errorf("GenerateIntGrid: different seeds gave the same maze")
+	}
+	if grid, exits := GenerateIntGrid(1, 12, 7); grid != nil || exits != nil {
+		t.Errorf("GenerateIntGrid: 1 x 12: want nil, got %d rows", len(grid))
+	}
+}
+
+func TestRectangleMazeDimensions(t *testing.T) {
+	constructors := []struct {
+		name string
+		new  func(height, width int) (*Rectangle, error)
+	}{
+		{"RectangleMaze", func(h, w int) (*Rectangle, error) { return RectangleMaze(h, w, true) }},
+		{"RectangleMazeAnimated", func(h, w int) (*Rectangle, error) { return RectangleMazeAnimated(h, w, true) }},
+		{"GenerateDungeon", func(h, w int) (*Rectangle, error) {
+			return GenerateDungeon(h, w, 0, rand.New(rand.NewSource(1)))
+		}},
+	}
+	for _, c := range constructors {
+		for _, size := range [][2]int{{0, 5}, {5, 0}, {1, 5}, {5, 1}, {-3, -3}} {
+			r, err := c.new(size[0], size[1])
+			if err == nil || r != nil {
+				t.Errorf("%s(%d, %d): want error, got %v", c.name, size[0], size[1], err)
+			}
+		}
+		// the smallest maze is 2 x 2
+		if r, err := c.new(2, 2); err != nil || r == nil {
+			t.Errorf("%s(2, 2): want maze, got %v", c.name, err)
+		}
+	}
+}
+
+func TestOpeningsMask(t *testing.T) {
+	// north is 1, east is 2, south is 4, and west is 8.
+	// the entrance opens north and the exit opens east.
+	want := [][]uint8{
+		{1 | 2 | 4, 2 | 8, 2 | 4 | 8},
+		{1 | 2 | 4, 8, 1 | 4},
+		{1 | 2, 2 | 8, 1 | 8},
+	}
+	got := loopMaze(t).OpeningsMask()
+	for row := range want {
+		for col := range want[row] {
+			if got[row][col] != want[row][col] {
+				t.Errorf("OpeningsMask: (%d, %d): want %04b, got %04b", row, col, want[row][col], got[row][col])
+			}
+		}
+	}
+}
+
+func TestForEachCell(t *testing.T) {
+	r := loopMaze(t)
+	mask := r.OpeningsMask()
+	calls := 0
+	r.ForEachCell(func(row, col int, n, e, s, w bool) {
+		// cells are visited in row-major order
+		if want := [2]int{calls / 3, calls % 3}; row != want[0] || col != want[1] {
+			t.Errorf("ForEachCell: call %d: want %v, got (%d, %d)", calls, want, row, col)
+		}
+		calls++
+		for dir, open := range []bool{n, e, s, w} {
+			if want := mask[row][col]&(1<<dir) != 0; open != want {
+				t.Errorf("ForEachCell: (%d, %d): %v: want open %v, got %v", row, col, Direction(dir), want, open)
+			}
+		}
+	})
+	if calls != 3*3 {
+		t.Errorf("ForEachCell: want %d calls, got %d", 3*3, calls)
+	}
+}
+
+func TestToggleWall(t *testing.T) {
+	r, err := RectangleMazeWith(6, 6, WilsonGenerator{}, true, WithSeed(4))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	before, mask := renderText(t, r), r.OpeningsMask()
+
+	// toggle every inner wall of one cell twice; each toggle changes both sides of the wall
+	for _, dir := range []Direction{North, East, South, West} {
+		if err := r.ToggleWall(2, 3, dir); err != nil {
+			t.Fatalf("ToggleWall %v: %v", dir, err)
+		}
+		c := r.g.cells[2][3]
+		if c.wall(dir) == (mask[2][3]&(1<<dir) == 0) || c.wall(dir) != c.neighbor(dir).wall(dir.opposite()) {
+			t.Errorf("ToggleWall %v: want both sides of the wall flipped", dir)
+		}
+		if r.IsPerfect() {
+			t.Errorf("ToggleWall %v: IsPerfect: want false, got true", dir)
+		}
+		if err := r.ToggleWall(2, 3, dir); err != nil {
+			t.Fatalf("ToggleWall %v: %v", dir, err)
+		}
+	}
+	if !r.IsPerfect() {
+		t.Errorf("ToggleWall: IsPerfect: want true after toggling back, got false")
+	}
+	// the solution was cleared by the edits, so solve again before comparing
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if after := renderText(t, r); after != before {
+		t.Errorf("ToggleWall: toggling twice: want\n%s\ngot\n%s", before, after)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		row, col int
+		dir      Direction
+	}{
+		{"out of bounds", 6, 0, North},
+		{"negative", -1, 0, South},
+		{"outer wall", 0, 0, North},
+		{"outer wall", 5, 5, East},
+	} {
+		if err := r.ToggleWall(tc.row, tc.col, tc.dir); err == nil {
+			t.Errorf("ToggleWall: %s: (%d, %d) %v: want error, got nil", tc.name, tc.row, tc.col, tc.dir)
+		}
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	r := loopMaze(t)
+	for _, path := range [][][2]int{
+		{{0, 0}, {0, 1}, {0, 2}},
+		{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {1, 2}, {0, 2}},
+	} {
+		if err := r.ValidatePath(path); err != nil {
+			t.Errorf("ValidatePath %v: want nil, got %v", path, err)
+		}
+	}
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if err := r.ValidatePath(r.SolutionPath()); err != nil {
+		t.Errorf("ValidatePath: solution: want nil, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		path [][2]int
+		want string
+	}{
+		{"empty", nil, "empty"},
+		{"wall", [][2]int{{0, 0}, {1, 0}, {1, 1}, {1, 2}, {0, 2}}, "wall blocks the way from cell (1, 1) to cell (1, 2)"},
+		{"gap", [][2]int{{0, 0}, {0, 1}, {2, 1}, {2, 2}, {1, 2}, {0, 2}}, "step 2: cell (2, 1) is not next to cell (0, 1)"},
+		{"diagonal", [][2]int{{0, 0}, {1, 1}}, "step 1: cell (1, 1) is not next to cell (0, 0)"},
+		{"out of bounds", [][2]int{{0, 0}, {-1, 0}}, "step 1: cell (-1, 0) is out of bounds"},
+		{"start", [][2]int{{0, 1}, {0, 2}}, "cell (0, 1) is not an entrance"},
+		{"end", [][2]int{{0, 0}, {0, 1}}, "cell (0, 1) is not an exit"},
+	} {
+		err := r.ValidatePath(tc.path)
+		if err == nil {
+			t.Errorf("ValidatePath: %s: want error, got nil", tc.name)
+		} else if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("ValidatePath: %s: want %q, got %q", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestRectangleMazeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if r, err := RectangleMazeContext(ctx, 20, 20, true); err != nil || r == nil {
+		t.Fatalf("RectangleMazeContext: want maze, got %v", err)
+	}
+
+	// cancel part way through the carving. the walks check the context before they start,
+	// so carving stops before every cell is added.
+	added := 0
+	progress := func(done, total int) {
+		if added = done; done == 10 {
+			cancel()
+		}
+	}
+	r, err := RectangleMazeWith(200, 200, WilsonGenerator{}, true, WithContext(ctx), WithProgress(progress))
+	if !errors.Is(err, context.Canceled) || r != nil {
+		t.Errorf("RectangleMazeWith: cancelled: want context.Canceled, got %v", err)
+	}
+	if added >= 200*200 {
+		t.Errorf("RectangleMazeWith: cancelled: want carving to stop early, got %d cells", added)
+	}
+
+	// the context is already cancelled, so nothing is carved. generators that don't check
+	// the context are stopped once they finish.
+	for _, gen := range []Generator{WilsonGenerator{}, KruskalGenerator{}} {
+		if r, err := RectangleMazeWith(10, 10, gen, false, WithContext(ctx)); !errors.Is(err, context.Canceled) || r != nil {
+			t.Errorf("%T: cancelled: want context.Canceled, got %v", gen, err)
+		}
+	}
+	if r, err := RectangleMazeContext(ctx, 10, 10, false); !errors.Is(err, context.Canceled) || r != nil {
+		t.Errorf("RectangleMazeContext: cancelled: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRectangleMazeProgress(t *testing.T) {
+	// every cell is reported once, in order
+	var calls []int
+	progress := func(done, total int) {
+		if total != 12*15 {
+			t.Fatalf("progress: total: want %d, got %d", 12*15, total)
+		}
+		calls = append(calls, done)
+	}
+	r, err := RectangleMazeProgress(12, 15, true, progress)
+	if err != nil {
+		t.Fatalf("RectangleMazeProgress: %v", err)
+	}
+	if !r.IsPerfect() {
+		t.Errorf("RectangleMazeProgress: maze is not perfect")
+	}
+	if len(calls) != 12*15 {
+		t.Fatalf("progress: want %d calls, got %d", 12*15, len(calls))
+	}
+	for n, done := range calls {
+		if done != n+1 {
+			t.Fatalf("progress: call %d: want done %d, got %d", n, n+1, done)
+		}
+	}
+
+	// progress may be nil
+	if _, err := RectangleMazeProgress(5, 5, true, nil); err != nil {
+		t.Errorf("RectangleMazeProgress: nil progress: %v", err)
+	}
+
+	// reporting progress doesn't change the maze
+	quiet, err := RectangleMazeWith(12, 15, WilsonGenerator{}, false, WithSeed(4))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	reported, err := RectangleMazeWith(12, 15, WilsonGenerator{}, false, WithSeed(4), WithProgress(func(done, total int) {}))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	if want, got := renderText(t, quiet), renderText(t, reported); got != want {
+		t.Errorf("WithProgress: want\n%s\ngot\n%s", want, got)
+	}
+}
+
+// recordingLogger is a Logger that keeps every message.
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...any) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestSetLogger(t *testing.T) {
+	t.Cleanup(func() { SetLogger(nil) })
+
+	// the package is quiet by default, and doesn't write to the standard logger
+	var std bytes.Buffer
+	log.SetOutput(&std)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	if err := loopMaze(t).Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if std.Len() != 0 {
+		t.Errorf("Solve: default logger: want no output, got %q", std.String())
+	}
+
+	// the messages go to the logger that was set
+	l := &recordingLogger{}
+	SetLogger(l)
+	if err := loopMaze(t).Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if len(l.messages) != 2 || !strings.HasPrefix(l.messages[0], "maze: solving") || !strings.HasPrefix(l.messages[1], "maze: solved") {
+		t.Errorf("Solve: want solving and solved messages, got %q", l.messages)
+	}
+	if std.Len() != 0 {
+		t.Errorf("Solve: custom logger: want no output on the standard logger, got %q", std.String())
+	}
+
+	// setting nil turns the messages off again
+	SetLogger(nil)
+	if err := loopMaze(t).Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if len(l.messages) != 2 {
+		t.Errorf("Solve: nil logger: want no new messages, got %q", l.messages[2:])
+	}
+}
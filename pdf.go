@@ -0,0 +1,67 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// RenderPDF renders the maze as a single page vector PDF, drawing the same lines as RenderPNG.
+// the page is sized to fit the maze with a margin of half the scale, and the scale is in points.
+func (r *Rectangle) RenderPDF(w io.Writer, scale int) error {
+	height, width, lines := r.g.toLines(scale, scale/2)
+	return toPDF(w, height, width, lines)
+}
+
+// toPDF writes the lines as a minimal PDF document with one page.
+// walls are black, the solution path is red, and the entrance and exit markers are green and blue.
+func toPDF(w io.Writer, height, width int, lines []line) error {
+	// build the content stream. PDF puts the origin at the bottom left, so the y values are flipped.
+	content := &bytes.Buffer{}
+	fmt.Fprintf(content, "1 J 1 j\n")
+	for _, style := range []struct {
+		color string
+		width float64
+		want  func(l line) bool
+	}{
+		{color: "0 0 0", width: 1.5, want: line.isWall},
+		{color: "1 0 0", width: 1.5, want: func(l line) bool { return l.onPath }},
+		{color: "0 0.63 0", width: 1.5, want: func(l line) bool { return l.entrance }},
+		{color: "0 0 1", width: 1.5, want: func(l line) bool { return l.exit }},
+	} {
+		fmt.Fprintf(content, "%s RG %g w\n", style.color, style.width)
+		for _, l := range lines {
+			if style.want(l) {
+				fmt.Fprintf(content, "%g %g m %g %g l S\n", l.from.x, float64(height)-l.from.y, l.to.x, float64(height)-l.to.y)
+			}
+		}
+	}
+
+	// write the objects, remembering where each one starts for the cross-reference table
+	doc := &bytes.Buffer{}
+	doc.WriteString("%PDF-1.4\n")
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Contents 4 0 R >>", width, height),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+	var offsets []int
+	for n, object := range objects {
+		offsets = append(offsets, doc.Len())
+		fmt.Fprintf(doc, "%d 0 obj\n%s\nendobj\n", n+1, object)
+	}
+
+	// write the cross-reference table and the trailer
+	xref := doc.Len()
+	fmt.Fprintf(doc, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(doc, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(doc, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(doc.Bytes())
+	return err
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRenderPDF(t *testing.T) {
+	r := loopMaze(t)
+	var b bytes.Buffer
+	if err := r.RenderPDF(&b, 20); err != nil {
+		t.Fatalf("RenderPDF: %v", err)
+	}
+	pdf := b.String()
+	if !strings.HasPrefix(pdf, "%PDF-") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatalf("RenderPDF: want a PDF document, got\n%s", pdf)
+	}
+	// three cells of 20 points and a margin of 10 on each side
+	if !strings.Contains(pdf, "/MediaBox [0 0 80 80]") {
+		t.Errorf("RenderPDF: want an 80 x 80 page")
+	}
+
+	// the cross-reference table must point at the start of each object
+	xref := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllStringSubmatch(pdf, -1)
+	if len(xref) != 4 {
+		t.Fatalf("RenderPDF: want 4 objects in the cross-reference table, got %d", len(xref))
+	}
+	for n, entry := range xref {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := fmt.Sprintf("%d 0 obj\n", n+1); !strings.HasPrefix(pdf[offset:], want) {
+			t.Errorf("RenderPDF: object %d: offset %d doesn't start the object", n+1, offset)
+		}
+	}
+
+	// the page draws one line for every wall that RenderPNG draws
+	_, _, lines := r.g.toLines(20, 10)
+	walls := 0
+	for _, l := range lines {
+		if l.isWall() {
+			walls++
+		}
+	}
+	stream := pdf[strings.Index(pdf, "0 0 0 RG"):strings.Index(pdf, "1 0 0 RG")]
+	if got := strings.Count(stream, " l S\n"); got != walls {
+		t.Errorf("RenderPDF: walls: want %d lines, got %d", walls, got)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// GatePlacement controls which edges of the maze the entrance and exit are placed on.
+type GatePlacement int
+
+const (
+	// OppositeEdges puts the entrance on the western part of the northern edge and the exit
+	// on the eastern part of the southern edge. this is what RectangleMaze does.
+	OppositeEdges GatePlacement = iota
+	// SameEdge puts both gates on the northern edge, the entrance on the western part
+	// and the exit on the eastern part.
+	SameEdge
+	// AdjacentCorners puts the entrance in the northwest corner, opening north,
+	// and the exit in the northeast corner, opening east.
+	AdjacentCorners
+	// Random puts each gate on a random cell of a random edge. the gates are always different cells.
+	Random
+	// OppositeCorners puts the entrance in the northwest corner, opening north,
+	// and the exit in the southeast corner, opening south. it never uses the source.
+	OppositeCorners
+)
+
+// RectangleMazeWithGates creates a maze like RectangleMaze, placing the entrance and exit according to the placement.
+func RectangleMazeWithGates(height, width int, solve bool, placement GatePlacement) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, WilsonGenerator{}, solve, WithGatePlacement(placement))
+}
+
+// GateOptions controls how far along their edges the entrance and exit can be placed.
+// the zero value puts the entrance in the northwest corner and the exit in the southeast corner.
+type GateOptions struct {
+	// EntranceEdgeFraction is the part of the northern edge, measured from the western corner,
+	// that the entrance is picked from. 0 fixes the entrance at the corner, 0.5 allows the western
+	// half, and 1 allows the whole edge. RectangleMaze uses 1/6.
+	EntranceEdgeFraction float64
+	// ExitEdgeFraction is the part of the southern edge, measured from the eastern corner,
+	// that the exit is picked from.
+	ExitEdgeFraction float64
+}
+
+// RectangleMazeWithGateOptions creates a maze like RectangleMaze, placing the entrance and exit
+// within the parts of their edges given by the options. fractions outside of 0 to 1 are clamped.
+func RectangleMazeWithGateOptions(height, width int, solve bool, opts GateOptions) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, WilsonGenerator{}, solve, WithGateOptions(opts))
+}
+
+// placeGatesFor assigns an entrance and exit to the grid using the placement policy.
+// rng should be independent of the source used to carve the maze; see gateSource.
+func placeGatesFor(g *grid, rng *rand.Rand, placement GatePlacement) (entrance, exit *cell) {
+	// open flags the cell as a gate and removes its outer wall on the given edge
+	open := func(row, col int, edge Direction, isEntrance bool) *cell {
+		c := g.cells[row][col]
+		c.entrance, c.exit = isEntrance, !isEntrance
+		c.openGate(edge)
+		return c
+	}
+
+	switch placement {
+	case SameEdge:
+		theGate := max(1, g.width/6)
+		entrance = open(0, rng.Intn(theGate), North, true)
+		exit = open(0, g.width-1-rng.Intn(theGate), North, false)
+	case AdjacentCorners:
+		entrance = open(0, 0, North, true)
+		exit = open(0, g.width-1, East, false)
+	case OppositeCorners:
+		entrance = open(0, 0, North, true)
+		exit = open(g.height-1, g.width-1, South, false)
+	case Random:
+		// pick an edge, then a cell along it, until the exit lands on a different cell than the entrance
+		pick := func() (row, col int, edge Direction) {
+			edge = Direction(rng.Intn(4))
+			switch edge {
+			case North:
+				return 0, rng.Intn(g.width), edge
+			case East:
+				return rng.Intn(g.height), g.width - 1, edge
+			case South:
+				return g.height - 1, rng.Intn(g.width), edge
+			}
+			return rng.Intn(g.height), 0, edge
+		}
+		row, col, edge := pick()
+		entrance = open(row, col, edge, true)
+		for exit == nil {
+			if row, col, edge := pick(); g.cells[row][col] != entrance {
+				exit = open(row, col, edge, false)
+			}
+		}
+	default:
+		entrance, exit = placeGates(g, rng)
+	}
+	return entrance, exit
+}
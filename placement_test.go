@@ -0,0 +1,117 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+func TestGatePlacement(t *testing.T) {
+	const height, width = 9, 12
+	for _, tc := range []struct {
+		name      string
+		placement GatePlacement
+		// check returns true if the gates are where the placement puts them
+		check func(entrance, exit outerOpening) bool
+	}{
+		{"OppositeEdges", OppositeEdges, func(entrance, exit outerOpening) bool {
+			return entrance.side == North && entrance.col < width/2 && exit.side == South && exit.col >= width/2
+		}},
+		{"SameEdge", SameEdge, func(entrance, exit outerOpening) bool {
+			return entrance.side == North && entrance.col < width/2 && exit.side == North && exit.col >= width/2
+		}},
+		{"AdjacentCorners", AdjacentCorners, func(entrance, exit outerOpening) bool {
+			return entrance == outerOpening{0, 0, North} && exit == outerOpening{0, width - 1, East}
+		}},
+		{"OppositeCorners", OppositeCorners, func(entrance, exit outerOpening) bool {
+			return entrance == outerOpening{0, 0, North} && exit == outerOpening{height - 1, width - 1, South}
+		}},
+		{"Random", Random, func(entrance, exit outerOpening) bool {
+			return entrance.row != exit.row || entrance.col != exit.col
+		}},
+	} {
+		for seed := int64(1); seed <= 10; seed++ {
+			r, err := RectangleMazeWith(height, width, WilsonGenerator{}, true, WithGatePlacement(tc.placement), WithSeed(seed))
+			if err != nil {
+				t.Fatalf("%s: RectangleMazeWith: %v", tc.name, err)
+			}
+			openings := outerOpenings(r)
+			if len(openings) != 2 {
+				t.Fatalf("%s: seed %d: want 2 openings, got %v", tc.name, seed, openings)
+			}
+			var entrance, exit outerOpening
+			for _, o := range openings {
+				if c := r.g.cells[o.row][o.col]; c.entrance {
+					entrance = o
+				} else if c.exit {
+					exit = o
+				}
+			}
+			if !tc.check(entrance, exit) {
+				t.Errorf("%s: seed %d: entrance %v, exit %v", tc.name, seed, entrance, exit)
+			}
+			if r.SolutionPath() == nil {
+				t.Errorf("%s: seed %d: want a solution", tc.name, seed)
+			}
+		}
+	}
+}
+
+func TestGateOptions(t *testing.T) {
+	const height, width = 9, 12
+	for _, tc := range []struct {
+		fraction float64
+		// the entrance columns are counted from the west and the exit columns from the east
+		window int
+	}{
+		{0, 1},
+		{0.5, width / 2},
+		{1, width},
+	} {
+		entrances, exits := map[int]bool{}, map[int]bool{}
+		for seed := int64(1); seed <= 40; seed++ {
+			opts := GateOptions{EntranceEdgeFraction: tc.fraction, ExitEdgeFraction: tc.fraction}
+			r, err := RectangleMazeWith(height, width, WilsonGenerator{}, false, WithGateOptions(opts), WithSeed(seed))
+			if err != nil {
+				t.Fatalf("RectangleMazeWith: %v", err)
+			}
+			row, col := r.Entrance()
+			if row != 0 || col >= tc.window {
+				t.Errorf("fraction %g: entrance: want a column below %d on the top row, got (%d, %d)", tc.fraction, tc.window, row, col)
+			}
+			entrances[col] = true
+			row, col = r.Exit()
+			if row != height-1 || col < width-tc.window {
+				t.Errorf("fraction %g: exit: want a column from %d on the bottom row, got (%d, %d)", tc.fraction, width-tc.window, row, col)
+			}
+			exits[col] = true
+		}
+		// the gates move around within the window
+		if tc.window > 1 && (len(entrances) < 2 || len(exits) < 2) {
+			t.Errorf("fraction %g: want the gates in different columns, got entrances %v and exits %v", tc.fraction, entrances, exits)
+		}
+	}
+}
+
+func TestOppositeCorners(t *testing.T) {
+	for _, size := range [][2]int{{2, 2}, {2, 7}, {7, 2}, {5, 8}} {
+		height, width := size[0], size[1]
+		r, err := RectangleMazeWithGates(height, width, true, OppositeCorners)
+		if err != nil {
+			t.Fatalf("RectangleMazeWithGates(%d, %d): %v", height, width, err)
+		}
+		if row, col := r.Entrance(); row != 0 || col != 0 {
+			t.Errorf("%d x %d: entrance: want (0, 0), got (%d, %d)", height, width, row, col)
+		}
+		if row, col := r.Exit(); row != height-1 || col != width-1 {
+			t.Errorf("%d x %d: exit: want (%d, %d), got (%d, %d)", height, width, height-1, width-1, row, col)
+		}
+		// the entrance opens through the northern border and the exit through the southern border
+		want := []outerOpening{{0, 0, North}, {height - 1, width - 1, South}}
+		if got := outerOpenings(r); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("%d x %d: openings: want %v, got %v", height, width, want, got)
+		}
+		path := r.SolutionPath()
+		if len(path) == 0 || path[0] != [2]int{0, 0} || path[len(path)-1] != [2]int{height - 1, width - 1} {
+			t.Errorf("%d x %d: path: want from corner to corner, got %v", height, width, path)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"context"
+	"fmt"
+	svgo "github.com/ajstarks/svgo"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// polarCell is a cell in a circular grid.
+// row 0 is the single cell in the center and each following row is a ring around it.
+// columns run clockwise starting from the positive x axis.
+type polarCell struct {
+	row, col int
+	// cw and ccw are the neighbors in the same ring
+	cw, ccw *polarCell
+	// inward is the neighbor in the next ring toward the center
+	inward *polarCell
+	// outward are the neighbors in the next ring away from the center
+	outward []*polarCell
+	// set of all neighbors
+	neighborhood []*polarCell
+	// links is the set of neighbors that have no wall between them and this cell
+	links map[*polarCell]bool
+	// entrance is set to true if the cell is an entrance
+	entrance bool
+	// exit is set to true if the cell is an exit
+	exit bool
+	// in is set to true if the cell has been added to the maze
+	in bool
+	// onPath is set if the cell is on the path between the entrance and the exit
+	onPath bool
+	// to points the last cell visited in the walk
+	to *polarCell
+}
+
+// linkTo removes the wall between the cell and its neighbor.
+func (c *polarCell) linkTo(other *polarCell) {
+	c.links[other] = true
+	other.links[c] = true
+}
+
+// polarGrid contains all the cells in a circular maze.
+type polarGrid struct {
+	rings int
+	cells [][]*polarCell
+}
+
+// createPolarGrid creates a circular grid with the given number of rings, including the center cell.
+// rings are subdivided as they move outward so that cells stay roughly square.
+func createPolarGrid(rings int) *polarGrid {
+	g := &polarGrid{rings: rings, cells: make([][]*polarCell, rings)}
+
+	// allocate the cells, deciding how many cells each ring needs
+	g.cells[0] = []*polarCell{{row: 0, col: 0, links: make(map[*polarCell]bool)}}
+	rowHeight := 1 / float64(rings)
+	for row := 1; row < rings; row++ {
+		radius := float64(row) / float64(rings)
+		circumference := 2 * math.Pi * radius
+		previousCount := len(g.cells[row-1])
+		estimatedWidth := circumference / float64(previousCount)
+		ratio := int(math.Round(estimatedWidth / rowHeight))
+		count := previousCount * max(1, ratio)
+		g.cells[row] = make([]*polarCell, count)
+		for col := 0; col < count; col++ {
+			g.cells[row][col] = &polarCell{row: row, col: col, links: make(map[*polarCell]bool)}
+		}
+	}
+
+	// link neighboring cells
+	for row := 1; row < rings; row++ {
+		count := len(g.cells[row])
+		for col, c := range g.cells[row] {
+			c.cw = g.cells[row][(col+1)%count]
+			c.ccw = g.cells[row][(col+count-1)%count]
+			ratio := count / len(g.cells[row-1])
+			c.inward = g.cells[row-1][col/ratio]
+			c.inward.outward = append(c.inward.outward, c)
+		}
+	}
+	for _, ring := range g.cells {
+		for _, c := range ring {
+			for _, neighbor := range []*polarCell{c.cw, c.ccw, c.inward} {
+				if neighbor != nil && neighbor != c {
+					c.neighborhood = append(c.neighborhood, neighbor)
+				}
+			}
+			c.neighborhood = append(c.neighborhood, c.outward...)
+		}
+	}
+
+	return g
+}
+
+// Polar is a circular maze made from rings of cells.
+// the entrance is in the outer ring and the exit is the center cell.
+type Polar struct {
+	g        *polarGrid
+	entrance *polarCell
+	exit     *polarCell
+	solved   bool
+}
+
+// PolarMaze creates a circular maze with the given number of rings using Wilson's algorithm.
+func PolarMaze(rings int, solve bool) (*Polar, error) {
+	if rings < 2 {
+		return nil, fmt.Errorf("invalid rings %d: a polar maze needs at least 2 rings", rings)
+	}
+	rng := rand.New(rand.NewSource(rand.Int63()))
+	gates := gateSource(rng)
+	g := createPolarGrid(rings)
+
+	// carve the maze with wilson's algorithm, which cannot fail without a context to cancel
+	var cells []*polarCell
+	for _, ring := range g.cells {
+		cells = append(cells, ring...)
+	}
+	_ = wilsonGraph[*polarCell]{
+		cells: cells,
+		in:    func(c *polarCell) bool { return c.in },
+		add: func(from, to *polarCell) {
+			if to != nil {
+				from.linkTo(to)
+			}
+			from.in = true
+		},
+		neighbor: func(c *polarCell) *polarCell { return c.neighborhood[rng.Intn(len(c.neighborhood))] },
+		next:     func(c *polarCell) *polarCell { return c.to },
+		setNext:  func(c, next *polarCell) { c.to = next },
+	}.carve(context.Background(), rng)
+
+	// the entrance is a random cell in the outer ring and the exit is the center
+	outer := g.cells[rings-1]
+	entrance := outer[gates.Intn(len(outer))]
+	entrance.entrance = true
+	exit := g.cells[0][0]
+	exit.exit = true
+
+	p := &Polar{g: g, entrance: entrance, exit: exit}
+	if solve {
+		p.Solve()
+	}
+
+	return p, nil
+}
+
+// Solve finds the path from the entrance to the exit using breadth-first search
+// and flags the cells on it so that the renderer will show them.
+func (p *Polar) Solve() {
+	if p.solved {
+		return
+	}
+	for _, ring := range p.g.cells {
+		for _, c := range ring {
+			c.to, c.onPath = nil, false
+		}
+	}
+
+	visited := map[*polarCell]bool{p.entrance: true}
+	queue := []*polarCell{p.entrance}
+	for len(queue) != 0 && !visited[p.exit] {
+		current := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range current.neighborhood {
+			if current.links[neighbor] && !visited[neighbor] {
+				visited[neighbor] = true
+				neighbor.to = current
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	// flag each cell that is on the path between the entrance and the exit
+	if visited[p.exit] {
+		for c := p.exit; c != nil; c = c.to {
+			c.onPath = true
+		}
+	}
+
+	p.solved = true
+}
+
+// RenderSVG renders the maze as an SVG image. scale is the depth of each ring.
+// walls are drawn as arcs and radial lines, and the solution path, if solved, is drawn in red.
+func (p *Polar) RenderSVG(w io.Writer, scale int) error {
+	gutter := scale / 2
+	size := 2*p.g.rings*scale + 2*gutter
+	center := float64(size) / 2
+
+	// at returns the point at the given distance and angle from the center of the image
+	at := func(radius, theta float64) point {
+		return point{x: center + radius*math.Cos(theta), y: center + radius*math.Sin(theta)}
+	}
+	// arc returns the path data for an arc clockwise from one angle to another
+	arc := func(radius, from, to float64) string {
+		start, end := at(radius, from), at(radius, to)
+		return fmt.Sprintf("M %.2f %.2f A %.2f %.2f 0 0 1 %.2f %.2f", start.x, start.y, radius, radius, end.x, end.y)
+	}
+
+	canvas := svgo.New(w)
+	canvas.Start(size, size)
+	canvas.Rect(0, 0, size, size, "fill:white")
+	wallStyle := "fill:none;stroke:black"
+
+	for row := 1; row < p.g.rings; row++ {
+		theta := 2 * math.Pi / float64(len(p.g.cells[row]))
+		inner, outer := float64(row*scale), float64((row+1)*scale)
+		for col, c := range p.g.cells[row] {
+			ccwAngle, cwAngle := float64(col)*theta, float64(col+1)*theta
+			// the wall between this cell and the inner ring
+			if !c.links[c.inward] {
+				canvas.Path(arc(inner, ccwAngle, cwAngle), wallStyle)
+			}
+			// the wall between this cell and its clockwise neighbor
+			if !c.links[c.cw] {
+				from, to := at(inner, cwAngle), at(outer, cwAngle)
+				canvas.Path(fmt.Sprintf("M %.2f %.2f L %.2f %.2f", from.x, from.y, to.x, to.y), wallStyle)
+			}
+			// the outer wall of the maze, which is left open at the entrance
+			if row == p.g.rings-1 && !c.entrance {
+				canvas.Path(arc(outer, ccwAngle, cwAngle), wallStyle)
+			}
+		}
+	}
+
+	// draw the solution as a line through the centers of the cells on the path
+	if p.exit.onPath {
+		var d string
+		for c := p.exit; c != nil; c = c.to {
+			pt := point{x: center, y: center}
+			if c.row != 0 {
+				theta := 2 * math.Pi / float64(len(p.g.cells[c.row]))
+				pt = at((float64(c.row)+0.5)*float64(scale), (float64(c.col)+0.5)*theta)
+			}
+			if d == "" {
+				d = fmt.Sprintf("M %.2f %.2f", pt.x, pt.y)
+			} else {
+				d += fmt.Sprintf(" L %.2f %.2f", pt.x, pt.y)
+			}
+		}
+		canvas.Path(d, "fill:none;stroke:red")
+	}
+
+	canvas.End()
+	return nil
+}
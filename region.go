@@ -0,0 +1,43 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+// Regions labels every cell with the id of the connected region that it belongs to.
+// two cells are in the same region if there is a path of open passages between them.
+// ids start at 0 and are assigned in row-major order of the first cell in each region.
+// masked cells aren't part of any region and are labeled -1.
+//
+// every generator connects all of the cells, and braiding only adds passages, so a freshly
+// generated maze is a single region. mazes with closed-off parts, like the ones from
+// RectangleDecoy or ones with walls added by hand, have more than one.
+func (r *Rectangle) Regions() [][]int {
+	regions := make([][]int, r.g.height)
+	for row := range regions {
+		regions[row] = make([]int, r.g.width)
+		for col := range regions[row] {
+			regions[row][col] = -1
+		}
+	}
+
+	id := 0
+	for _, start := range r.g.allCells() {
+		if start.masked || regions[start.row][start.col] != -1 {
+			continue
+		}
+		// flood the region from the first unlabeled cell
+		regions[start.row][start.col] = id
+		queue := []*cell{start}
+		for len(queue) != 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range current.openNeighbors() {
+				if regions[neighbor.row][neighbor.col] == -1 {
+					regions[neighbor.row][neighbor.col] = id
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+		id++
+	}
+	return regions
+}
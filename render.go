@@ -3,29 +3,375 @@
 package maze
 
 import (
+	"bufio"
 	"bytes"
+	"fmt"
 	svgo "github.com/ajstarks/svgo"
 	"github.com/fogleman/gg"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
 	"io"
+	"math"
 )
 
+// PNGOptions controls the appearance of rendered PNG images.
+// fields that are not set use the defaults of a white background with black walls
+// and a red solution path, all drawn 3 pixels wide, with a margin of half the scale.
+// the entrance is marked in green and the exit in blue.
+type PNGOptions struct {
+	Background color.Color
+	Wall       color.Color
+	Path       color.Color
+	Entrance   color.Color
+	Exit       color.Color
+	LineWidth  float64
+	// BorderLineWidth is the width of the walls on the outer edges of the maze.
+	// it defaults to LineWidth; set it larger to frame the maze with a heavier border.
+	BorderLineWidth float64
+	// CorridorRatio is the width of the passages divided by the width of the walls.
+	// if it is set, it overrides the scale: the walls are LineWidth wide and each cell is sized to hold
+	// one wall and one passage that is ratio times as wide, so a line width of 1 with a ratio of 40
+	// draws walls 1 pixel wide and passages 40 pixels wide whatever the scale.
+	CorridorRatio float64
+	// Margin is the number of pixels between the maze and the edges of the image.
+	Margin int
+	// RoundedCaps draws the ends of lines with round caps, so thick walls have rounded corners.
+	// otherwise the caps are square and the corners are sharp.
+	RoundedCaps bool
+	// NoAntiAlias removes the anti-aliasing from the image. gg can't draw without it, so the image
+	// is drawn as usual and then every pixel is snapped to the closest of the option colors,
+	// which leaves hard edges and a small palette.
+	NoAntiAlias bool
+}
+
+// withDefaults returns a copy of the options with the defaults applied to unset fields.
+func (opts PNGOptions) withDefaults() PNGOptions {
+	if opts.Background == nil {
+		opts.Background = color.White
+	}
+	if opts.Wall == nil {
+		opts.Wall = color.Black
+	}
+	if opts.Path == nil {
+		opts.Path = color.RGBA{R: 255, A: 255}
+	}
+	if opts.Entrance == nil {
+		opts.Entrance = color.RGBA{G: 160, A: 255}
+	}
+	if opts.Exit == nil {
+		opts.Exit = color.RGBA{B: 255, A: 255}
+	}
+	if opts.LineWidth <= 0 {
+		opts.LineWidth = 3
+	}
+	if opts.BorderLineWidth <= 0 {
+		opts.BorderLineWidth = opts.LineWidth
+	}
+	return opts
+}
+
 func (r *Rectangle) RenderPNG(w io.Writer, scale int) error {
+	return png.Encode(w, r.RenderImage(scale))
+}
+
+// RenderImage renders the maze as an in-memory image, using the same layout and colors as RenderPNG.
+// use it to draw the maze into a larger image without encoding and decoding a PNG.
+func (r *Rectangle) RenderImage(scale int) *image.RGBA {
+	height, width, lines := r.g.toLines(scale, gutterFor(scale, 0))
+	return r.g.toImage(height, width, lines, PNGOptions{}.withDefaults())
+}
+
+// RenderPathPNG renders the maze as a PNG image, drawing the solution path in the given color.
+// the path is only drawn if the maze has been solved.
+func (r *Rectangle) RenderPathPNG(w io.Writer, scale int, pathColor color.Color) error {
+	return r.RenderPNGWithOptions(w, scale, PNGOptions{Path: pathColor})
+}
+
+// RenderPNGWithOptions renders the maze as a PNG image using the colors and line width from the options.
+func (r *Rectangle) RenderPNGWithOptions(w io.Writer, scale int, opts PNGOptions) error {
+	opts = opts.withDefaults()
+	if opts.CorridorRatio > 0 {
+		scale = corridorScale(opts.LineWidth, opts.CorridorRatio)
+	}
+	height, width, lines := r.g.toLines(scale, gutterFor(scale, opts.Margin))
+	return r.g.toPNG(w, height, width, lines, opts)
+}
+
+// RenderPNGScaled renders the maze as a PNG image with rectangular cells that are scaleX pixels wide
+// and scaleY pixels tall. the margin is half the smaller scale.
+func (r *Rectangle) RenderPNGScaled(w io.Writer, scaleX, scaleY int) error {
+	if scaleX < 1 || scaleY < 1 {
+		return fmt.Errorf("invalid scale %d x %d", scaleX, scaleY)
+	}
+	height, width, lines := r.g.toLinesXY(scaleX, scaleY, min(scaleX, scaleY)/2)
+	return r.g.toPNG(w, height, width, lines, PNGOptions{}.withDefaults())
+}
+
+// RenderPNGRegion renders part of the maze as a PNG image. the region starts with the cell at row0, col0
+// and is rows cells tall and cols cells wide. walls on the edges of the region are drawn, so regions
+// can be used as tiles. it returns an error if the region is empty or isn't inside the maze.
+func (r *Rectangle) RenderPNGRegion(w io.Writer, scale, row0, col0, rows, cols int) error {
+	if rows < 1 || cols < 1 {
+		return fmt.Errorf("region: invalid size %d x %d", rows, cols)
+	} else if !r.g.inBounds(row0, col0) || !r.g.inBounds(row0+rows-1, col0+cols-1) {
+		return fmt.Errorf("region: cells (%d, %d) to (%d, %d) are out of bounds", row0, col0, row0+rows-1, col0+cols-1)
+	}
+	height, width, lines := r.g.toLinesWindow(scale, scale, scale/2, row0, col0, rows, cols)
+	return r.g.toPNG(w, height, width, lines, PNGOptions{}.withDefaults())
+}
+
+// RenderPNGStrips renders the maze as a set of PNG images, each holding rowsPerStrip rows of cells
+// (the last strip may hold fewer). only one strip is in memory at a time, so very large mazes can be
+// rendered without allocating one giant image. create is called with the number of each strip, starting
+// at 0, and the strip is written to the writer that it returns, which is closed after the strip is written.
+// the margin of half the scale is kept only on the outside of the maze: the first strip has the top margin,
+// the last strip has the bottom margin, and the walls between strips are split across them, so stacking
+// the strips in order gives the same image as RenderPNG.
+func (r *Rectangle) RenderPNGStrips(scale, rowsPerStrip int, create func(strip int) (io.WriteCloser, error)) error {
+	if rowsPerStrip < 1 {
+		return fmt.Errorf("strips: invalid rows per strip %d", rowsPerStrip)
+	}
+	gutter := gutterFor(scale, 0)
+	opts := PNGOptions{}.withDefaults()
+	for strip, row0 := 0, 0; row0 < r.g.height; strip, row0 = strip+1, row0+rowsPerStrip {
+		w, err := create(strip)
+		if err != nil {
+			return err
+		}
+		rows := min(rowsPerStrip, r.g.height-row0)
+		// draw the rows on either side of the strip too, so that the walls and caps that cross
+		// the edges of the strip are drawn the same as in the full image, then trim them away
+		// along with the margins that fall between this strip and its neighbors.
+		first, last := max(row0-1, 0), min(row0+rows+1, r.g.height)
+		height, width, lines := r.g.toLinesWindow(scale, scale, gutter, first, 0, last-first, r.g.width)
+		top, bottom := 0, height
+		if row0 != 0 {
+			top = gutter + (row0-first)*scale
+		}
+		if row0+rows != r.g.height {
+			bottom = gutter + (row0+rows-first)*scale
+		}
+		img := r.g.toImage(height, width, lines, opts).SubImage(image.Rect(0, top, width, bottom))
+		err = png.Encode(w, img)
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("strips: strip %d: %w", strip, err)
+		}
+	}
+	return nil
+}
+
+// RenderBlockPNG renders the maze as a PNG image in block style, where walls fill whole squares
+// instead of being drawn as lines. it uses the same layout as ToGrid: cells and the passages
+// between them are open squares and walls and corners are filled squares. the rows and columns
+// that hold cells are cellPx pixels wide and the rows and columns between them are wallPx wide.
+func (r *Rectangle) RenderBlockPNG(w io.Writer, cellPx, wallPx int) error {
+	if cellPx < 1 || wallPx < 1 {
+		return fmt.Errorf("invalid block size: cell %d, wall %d", cellPx, wallPx)
+	} else if r.g.hasCrossings() {
+		return fmt.Errorf("block: weave crossings can't be rendered as blocks")
+	}
+	return png.Encode(w, r.g.toBlockImage(cellPx, wallPx, PNGOptions{}.withDefaults()))
+}
+
+// RenderWeightedPNG renders the maze as a PNG image, shading each cell by its weight before drawing the walls.
+// weights must have the same dimensions as the maze. they are normalized to [0, 1] before shading.
+func (r *Rectangle) RenderWeightedPNG(w io.Writer, weights [][]float64, scale int) error {
+	shades, err := r.g.normalizeWeights(weights)
+	if err != nil {
+		return err
+	}
 	height, width, lines := r.g.toLines(scale, scale/2)
-	return r.g.toPNG(w, height, width, lines)
+	return r.g.toWeightedPNG(w, height, width, scale, scale/2, shades, lines)
 }
 
-func (r *Rectangle) RenderSVG(w io.Writer, scale int) error {
+// RenderHeatmapPNG renders the maze as a PNG image, filling each cell with a color based on its distance
+// from the entrance. cells near the entrance are blue and the farthest cells are red.
+// cells that can't be reached from the entrance are left white.
+func (r *Rectangle) RenderHeatmapPNG(w io.Writer, scale int) error {
+	height, width, lines := r.g.toLines(scale, scale/2)
+	return r.g.toHeatmapPNG(w, height, width, scale, scale/2, r.g.distancesFrom(r.entrances[0]), lines)
+}
+
+// RenderTrailPNG renders the maze as a PNG image with a dot at the center of each cell in the trail.
+// the dots fade toward the start of the trail, so the oldest cell is the faintest.
+// cells that are visited more than once are drawn only for their most recent visit.
+func (r *Rectangle) RenderTrailPNG(w io.Writer, trail []CellInfo, scale int) error {
+	for _, ci := range trail {
+		if !r.g.inBounds(ci.Row, ci.Col) {
+			return fmt.Errorf("trail: cell (%d, %d) is out of bounds", ci.Row, ci.Col)
+		}
+	}
 	height, width, lines := r.g.toLines(scale, scale/2)
-	return r.g.toSVG(w, height, width, lines)
+	return r.g.toTrailPNG(w, height, width, scale, scale/2, trail, lines)
+}
+
+// SVGOptions controls the appearance of rendered SVG images.
+// the styles are CSS declarations; fields that are not set use the defaults of
+// "stroke:black" for walls, "fill:white" for the background, "stroke:red" for the solution path,
+// "stroke:green" for the entrance marker, and "stroke:blue" for the exit marker.
+// arrows are drawn in the margin at the openings of the gates, pointing into the entrance
+// and out of the exit; they use "fill:green" and "fill:blue" unless NoArrows is set.
+// if Class is set, it is added as the class attribute of every line.
+// if Margin is not set, the margin is half the scale.
+type SVGOptions struct {
+	WallStyle       string
+	BackgroundStyle string
+	PathStyle       string
+	EntranceStyle   string
+	ExitStyle       string
+	// EntranceArrowStyle and ExitArrowStyle style the arrows at the gates
+	EntranceArrowStyle string
+	ExitArrowStyle     string
+	// NoArrows turns off the arrows at the gates.
+	NoArrows bool
+	Class    string
+	// CorridorRatio is the width of the passages divided by the width of the walls, like PNGOptions.CorridorRatio.
+	// if it is set, it overrides the scale and the stroke width of the walls is added to the wall style.
+	CorridorRatio float64
+	// LineWidth is the width of the walls when CorridorRatio is set. it defaults to 3, like the PNG walls.
+	LineWidth float64
+	// Margin is the number of pixels between the maze and the edges of the image.
+	Margin int
 }
 
+// withDefaults returns a copy of the options with the defaults applied to unset fields.
+func (opts SVGOptions) withDefaults() SVGOptions {
+	if opts.WallStyle == "" {
+		opts.WallStyle = "stroke:black"
+	}
+	if opts.BackgroundStyle == "" {
+		opts.BackgroundStyle = "fill:white"
+	}
+	if opts.PathStyle == "" {
+		opts.PathStyle = "stroke:red"
+	}
+	if opts.EntranceStyle == "" {
+		opts.EntranceStyle = "stroke:green"
+	}
+	if opts.ExitStyle == "" {
+		opts.ExitStyle = "stroke:blue"
+	}
+	if opts.EntranceArrowStyle == "" {
+		opts.EntranceArrowStyle = "fill:green"
+	}
+	if opts.ExitArrowStyle == "" {
+		opts.ExitArrowStyle = "fill:blue"
+	}
+	if opts.LineWidth <= 0 {
+		opts.LineWidth = 3
+	}
+	return opts
+}
+
+func (r *Rectangle) RenderSVG(w io.Writer, scale int) error {
+	return r.RenderSVGWithOptions(w, scale, SVGOptions{})
+}
+
+// RenderSVGWithOptions renders the maze as an SVG image using the styles from the options.
+func (r *Rectangle) RenderSVGWithOptions(w io.Writer, scale int, opts SVGOptions) error {
+	opts = opts.withDefaults()
+	if opts.CorridorRatio > 0 {
+		scale = corridorScale(opts.LineWidth, opts.CorridorRatio)
+		opts.WallStyle += fmt.Sprintf(";stroke-width:%g", opts.LineWidth)
+	}
+	gutter := gutterFor(scale, opts.Margin)
+	height, width, lines := r.g.toLines(scale, gutter)
+	var arrows []arrow
+	if !opts.NoArrows {
+		arrows = r.g.toArrows(scale, gutter)
+	}
+	return r.g.toSVG(w, height, width, lines, arrows, opts)
+}
+
+// corridorScale returns the size of a cell that holds one wall of the given width and one passage
+// that is ratio times as wide, rounded to the nearest pixel. it is never less than 2 pixels.
+func corridorScale(wall, ratio float64) int {
+	return max(int(math.Round(wall*(ratio+1))), 2)
+}
+
+// gutterFor returns the margin if it is set, otherwise the default gutter of half the scale.
+func gutterFor(scale, margin int) int {
+	if margin > 0 {
+		return margin
+	}
+	return scale / 2
+}
+
+// RenderText renders the maze as text using IBM box glyphs, with every cell doubled so that walls
+// and corners get their own glyph. the center of the entrance is marked 'E' and the center of the exit 'X'.
+// if the maze has been solved, the center of every cell on the solution path except the entrance and
+// the exit is marked '*', so there is one marker for each cell between the gates.
 func (r *Rectangle) RenderText(w io.Writer) error {
 	return r.g.toText(w)
 }
 
+// RenderTextStream renders the maze as text like RenderText, but writes it one line at a time
+// instead of building the whole image in memory first. the output is identical to RenderText.
+func (r *Rectangle) RenderTextStream(w io.Writer) error {
+	return r.g.toTextStream(w)
+}
+
+// RenderTextASCII renders the maze as text using only ASCII characters,
+// for terminals and logs where the box glyphs used by RenderText aren't available.
+func (r *Rectangle) RenderTextASCII(w io.Writer) error {
+	return r.g.toASCII(w)
+}
+
+// RenderTextStyled renders the maze as text like RenderText, using the glyphs from the style.
+func (r *Rectangle) RenderTextStyled(w io.Writer, style TextStyle) error {
+	return r.g.toStyledText(w, style)
+}
+
+// RenderTextLabeled renders the maze as text like RenderText, with the column numbers printed above
+// and below the maze and the row numbers printed to the left of each row of cells.
+// column numbers are written vertically, one digit per line, so they line up with the cells.
+func (r *Rectangle) RenderTextLabeled(w io.Writer) error {
+	return r.g.toLabeledText(w)
+}
+
+// ToGrid returns the maze as a grid of integers, using 0 for a path and 1 for a wall.
+// every cell is doubled, like the text renderer, so the grid has 2*height+1 rows and 2*width+1 columns.
+// this is the format used by the reachability check in cmd/solver.
+// it returns nil for weave mazes, since the grid has no way to show a tunnel beneath a crossing.
+func (r *Rectangle) ToGrid() [][]int {
+	if r.g.hasCrossings() {
+		return nil
+	}
+	return r.g.toIntGrid()
+}
+
 type line struct {
 	from, to point
 	onPath   bool
+	// border is set for walls on the outer edges of the grid
+	border bool
+	// entrance and exit are set for the lines that mark the gates
+	entrance bool
+	exit     bool
+}
+
+// shorten returns a copy of the line with both ends moved toward the middle by the given amount.
+func (l line) shorten(amount float64) line {
+	dx, dy := l.to.x-l.from.x, l.to.y-l.from.y
+	length := math.Hypot(dx, dy)
+	if length <= 2*amount {
+		return l
+	}
+	ux, uy := dx/length*amount, dy/length*amount
+	l.from = point{x: l.from.x + ux, y: l.from.y + uy}
+	l.to = point{x: l.to.x - ux, y: l.to.y - uy}
+	return l
+}
+
+// isWall returns true if the line is a wall rather than a marker.
+func (l line) isWall() bool {
+	return !l.onPath && !l.entrance && !l.exit
 }
 
 type point struct {
@@ -35,43 +381,79 @@ type point struct {
 // toLines renders the grid as a set of lines.
 // each cell is scaled and a gutter is added to the final image.
 func (g *grid) toLines(scale int, gutter int) (height int, width int, lines []line) {
+	return g.toLinesXY(scale, scale, gutter)
+}
+
+// toLinesXY renders the grid as a set of lines, using separate scales for the width and height of a cell.
+// markers are sized to fit the shorter side of the cell.
+func (g *grid) toLinesXY(scaleX, scaleY int, gutter int) (height int, width int, lines []line) {
+	return g.toLinesWindow(scaleX, scaleY, gutter, 0, 0, g.height, g.width)
+}
+
+// toLinesWindow renders a window of the grid as a set of lines. the window starts with the cell at row0, col0
+// and is rows cells tall and cols cells wide. the window must be inside the grid.
+// the cells on the edges of the window draw their own walls, so the boundary is correct.
+func (g *grid) toLinesWindow(scaleX, scaleY int, gutter int, row0, col0, rows, cols int) (height int, width int, lines []line) {
 	// set the width and height of the image, assuming cells are scaled and including room for the gutter
-	width, height = g.width*scale+gutter*2, g.height*scale+gutter*2
-
-	// the offset will be half the scale and allows for the gutter
-	offset := scale/2 + gutter
-	for x := 0; x < g.width; x++ {
-		// derive the center x value of the cell in the image, assuming cells are 10x10
-		cx := x*scale + offset
-		for y := 0; y < g.height; y++ {
+	width, height = cols*scaleX+gutter*2, rows*scaleY+gutter*2
+
+	// the markers have to fit in the cell, so they are sized from the shorter side
+	scale := min(scaleX, scaleY)
+
+	// the offsets will be half the scale and allow for the gutter.
+	// they also shift the first cell in the window to the corner of the image.
+	offsetX, offsetY := scaleX/2+gutter-col0*scaleX, scaleY/2+gutter-row0*scaleY
+	for x := col0; x < col0+cols; x++ {
+		// derive the center x value of the cell in the image
+		cx := x*scaleX + offsetX
+		for y := row0; y < row0+rows; y++ {
 			// c is the cell that we're adding to the image
 			c := g.cells[y][x]
+			if c.masked {
+				// masked cells aren't drawn; their unmasked neighbors draw the walls between them
+				continue
+			}
 
 			// derive the center y value of the cell in the image
-			cy := y*scale + offset
+			cy := y*scaleY + offsetY
 
-			// derive values for the four corners of the cell
+			// derive values for the four corners of the cell.
+			// the far corners are a whole scale from the near ones, so that when the scale is odd
+			// the walls of neighboring cells still land on the same line.
+			left, top := cx-scaleX/2, cy-scaleY/2
 			cp := point{x: float64(cx), y: float64(cy)}
-			nw := point{x: float64(cx - scale/2), y: float64(cy - scale/2)}
-			ne := point{x: float64(cx + scale/2), y: float64(cy - scale/2)}
-			sw := point{x: float64(cx - scale/2), y: float64(cy + scale/2)}
-			se := point{x: float64(cx + scale/2), y: float64(cy + scale/2)}
+			nw := point{x: float64(left), y: float64(top)}
+			ne := point{x: float64(left + scaleX), y: float64(top)}
+			sw := point{x: float64(left), y: float64(top + scaleY)}
+			se := point{x: float64(left + scaleX), y: float64(top + scaleY)}
+
+			// remember where this cell's walls start in case they need to be shortened
+			walls := len(lines)
 
 			// if there is a wall blocking the path north, draw a line from NW to NE corners.
+			// walls without a neighbor on the other side are on the outer edge of the grid.
 			if c.walls.north {
-				lines = append(lines, line{from: nw, to: ne})
+				lines = append(lines, line{from: nw, to: ne, border: c.neighbors.north == nil})
 			}
 			// if there is a wal blocking the path east, draw a line from the NE to SE corners.
 			if c.walls.east {
-				lines = append(lines, line{from: ne, to: se})
+				lines = append(lines, line{from: ne, to: se, border: c.neighbors.east == nil})
 			}
 			// if there is a wall blocking the path south, draw a line from SE to SW corners.
 			if c.walls.south {
-				lines = append(lines, line{from: se, to: sw})
+				lines = append(lines, line{from: se, to: sw, border: c.neighbors.south == nil})
 			}
 			// if there is a wall blocking the path west, draw a line from the SW to NW corners.
 			if c.walls.west {
-				lines = append(lines, line{from: sw, to: nw})
+				lines = append(lines, line{from: sw, to: nw, border: c.neighbors.west == nil})
+			}
+			// a cell with a tunnel beneath it is a bridge. its walls stop short of the corners,
+			// leaving gaps that show the walls of the tunnel passing underneath.
+			if c.under {
+				gap := float64(scale) / 5
+				for n := walls; n < len(lines); n++ {
+					lines[n] = lines[n].shorten(gap)
+				}
 			}
 			// if the cell is on the path between the entrance and the exit, mark it.
 			// (note that the flag is only set if the user created the grid with the `solve` flag set.)
@@ -85,6 +467,27 @@ func (g *grid) toLines(scale int, gutter int) (height int, width int, lines []li
 				lenDash := float64(scale/2) * 0.33
 				lines = append(lines, line{from: point{x: cp.x, y: cp.y - lenDash}, to: point{x: cp.x, y: cp.y + lenDash}, onPath: true})
 				lines = append(lines, line{from: point{x: cp.x - lenDash, y: cp.y}, to: point{x: cp.x + lenDash, y: cp.y}, onPath: true})
+
+				// connect the center of this cell to the center of the previous cell on the path
+				if prev := c.to; prev != nil && prev.onPath {
+					pp := point{x: float64(prev.col*scaleX + offsetX), y: float64(prev.row*scaleY + offsetY)}
+					if dir, ok := c.wrapsTo(prev); ok {
+						// the path wraps around the edge of a toroidal maze, so draw it as two stubs
+						// that run off opposite edges instead of a line across the whole maze
+						lines = append(lines, line{from: cp, to: toEdge(cp, dir, scaleX, scaleY), onPath: true})
+						lines = append(lines, line{from: pp, to: toEdge(pp, dir.opposite(), scaleX, scaleY), onPath: true})
+					} else {
+						lines = append(lines, line{from: cp, to: pp, onPath: true})
+					}
+				}
+			}
+			// mark the entrance and exit with a small diamond in the center of the cell
+			if c.entrance || c.exit {
+				r := float64(scale/2) * 0.5
+				n, e, s, w := point{x: cp.x, y: cp.y - r}, point{x: cp.x + r, y: cp.y}, point{x: cp.x, y: cp.y + r}, point{x: cp.x - r, y: cp.y}
+				for _, side := range [][2]point{{n, e}, {e, s}, {s, w}, {w, n}} {
+					lines = append(lines, line{from: side[0], to: side[1], entrance: c.entrance, exit: c.exit && !c.entrance})
+				}
 			}
 		}
 	}
@@ -92,28 +495,273 @@ func (g *grid) toLines(scale int, gutter int) (height int, width int, lines []li
 	return height, width, lines
 }
 
+// toEdge returns the point on the edge of the cell in the given direction from its center.
+// when the scale is odd, the center is closer to the northern and western edges.
+func toEdge(center point, dir Direction, scaleX, scaleY int) point {
+	switch dir {
+	case North:
+		return point{x: center.x, y: center.y - float64(scaleY/2)}
+	case East:
+		return point{x: center.x + float64(scaleX-scaleX/2), y: center.y}
+	case South:
+		return point{x: center.x, y: center.y + float64(scaleY-scaleY/2)}
+	}
+	return point{x: center.x - float64(scaleX/2), y: center.y}
+}
+
+// arrow is a triangle drawn next to the opening of a gate.
+type arrow struct {
+	points   [3]point
+	entrance bool
+}
+
+// toArrows returns an arrow for every gate that has an opening in the outer wall, using the layout from toLines.
+// the arrows sit in the gutter, pointing into the entrances and out of the exits.
+func (g *grid) toArrows(scale, gutter int) (arrows []arrow) {
+	// the arrow has to fit in the gutter and be narrower than the opening
+	size := 0.8 * float64(min(gutter, scale/2))
+	for _, c := range g.allCells() {
+		if !c.entrance && !c.exit {
+			continue
+		}
+		for _, dir := range []Direction{North, East, South, West} {
+			if c.neighbor(dir) != nil || c.wall(dir) {
+				continue
+			}
+			center := point{x: float64(c.col*scale + scale/2 + gutter), y: float64(c.row*scale + scale/2 + gutter)}
+			edge := toEdge(center, dir, scale, scale)
+			// out points away from the maze and across runs along the wall
+			out := toEdge(point{}, dir, 2, 2)
+			across := point{x: -out.y, y: out.x}
+			base := point{x: edge.x + out.x*size, y: edge.y + out.y*size}
+			tip := edge
+			if !c.entrance {
+				// exits point away from the maze, so the base sits on the wall
+				base, tip = edge, base
+			}
+			arrows = append(arrows, arrow{
+				points: [3]point{
+					tip,
+					{x: base.x + across.x*size/2, y: base.y + across.y*size/2},
+					{x: base.x - across.x*size/2, y: base.y - across.y*size/2},
+				},
+				entrance: c.entrance,
+			})
+		}
+	}
+	return arrows
+}
+
 // toPNG renders the grid as a PNG image file.
 // each cell is scaled and a gutter is added to the final image.
-func (g *grid) toPNG(w io.Writer, height, width int, lines []line) error {
+func (g *grid) toPNG(w io.Writer, height, width int, lines []line, opts PNGOptions) error {
+	return png.Encode(w, g.toImage(height, width, lines, opts))
+}
+
+// toImage renders the grid as an in-memory image.
+func (g *grid) toImage(height, width int, lines []line, opts PNGOptions) *image.RGBA {
+	dc := gg.NewContext(width, height)
+
+	// set the background of the image
+	dc.SetColor(opts.Background)
+	dc.Clear()
+
+	// draw the walls and path markers
+	drawLines(dc, lines, opts)
+
+	img := dc.Image().(*image.RGBA)
+
+	// gg always anti-aliases, so remove it by snapping the pixels back to the palette
+	if opts.NoAntiAlias {
+		snapToPalette(img, []color.Color{opts.Background, opts.Wall, opts.Path, opts.Entrance, opts.Exit})
+	}
+
+	return img
+}
+
+// snapToPalette replaces every pixel in the image with the closest color from the palette.
+func snapToPalette(img *image.RGBA, palette []color.Color) {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			img.Set(x, y, color.Palette(palette).Convert(img.At(x, y)))
+		}
+	}
+}
+
+// toBlockImage renders the int grid as an image, filling a rectangle for every wall element.
+// even rows and columns are walls and corners and are wallPx pixels wide; odd ones hold cells and are cellPx wide.
+func (g *grid) toBlockImage(cellPx, wallPx int, opts PNGOptions) *image.RGBA {
+	// offsets returns the pixel offset of every element and the total size in pixels
+	offsets := func(n int) ([]int, int) {
+		offset := make([]int, n+1)
+		for i := 0; i < n; i++ {
+			size := wallPx
+			if i%2 == 1 {
+				size = cellPx
+			}
+			offset[i+1] = offset[i] + size
+		}
+		return offset, offset[n]
+	}
+
+	maze := g.toIntGrid()
+	rowOffset, height := offsets(len(maze))
+	colOffset, width := offsets(len(maze[0]))
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+	wall := image.NewUniform(opts.Wall)
+	for row := range maze {
+		for col := range maze[row] {
+			if maze[row][col] == 1 {
+				rect := image.Rect(colOffset[col], rowOffset[row], colOffset[col+1], rowOffset[row+1])
+				draw.Draw(img, rect, wall, image.Point{}, draw.Src)
+			}
+		}
+	}
+	return img
+}
+
+// toWeightedPNG renders the grid as a PNG image file, shading each cell before drawing the walls.
+// shades must be normalized to [0, 1] and is used as the opacity of the shading.
+func (g *grid) toWeightedPNG(w io.Writer, height, width, scale, gutter int, shades [][]float64, lines []line) error {
+	dc := gg.NewContext(width, height)
+
+	// set the background of the image to white
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	// shade each cell in blue, using the weight as the opacity
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			if shades[row][col] <= 0 {
+				continue
+			}
+			dc.SetRGBA(0, 0, 1, shades[row][col])
+			dc.DrawRectangle(float64(col*scale+gutter), float64(row*scale+gutter), float64(scale), float64(scale))
+			dc.Fill()
+		}
+	}
+
+	// draw the walls and path markers on top of the shading
+	drawLines(dc, lines, PNGOptions{}.withDefaults())
+
+	// write the image as PNG
+	err := dc.EncodePNG(w)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// toHeatmapPNG renders the grid as a PNG image file, filling each cell with a color interpolated
+// from blue to red by its distance before drawing the walls. unreachable cells (-1) are not filled.
+func (g *grid) toHeatmapPNG(w io.Writer, height, width, scale, gutter int, distances [][]int, lines []line) error {
 	dc := gg.NewContext(width, height)
 
 	// set the background of the image to white
 	dc.SetRGB(1, 1, 1)
 	dc.Clear()
 
-	// draw walls as black lines, 3 pixels wide
-	dc.SetRGB(0, 0, 0)
-	dc.SetLineWidth(3)
+	// find the farthest distance so that the colors use the full range
+	farthest := 0
+	for _, row := range distances {
+		for _, distance := range row {
+			farthest = max(farthest, distance)
+		}
+	}
+
+	// fill each reachable cell, shifting from blue to red as the distance grows
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			if distances[row][col] < 0 {
+				continue
+			}
+			t := 0.0
+			if farthest > 0 {
+				t = float64(distances[row][col]) / float64(farthest)
+			}
+			dc.SetRGB(t, 0, 1-t)
+			dc.DrawRectangle(float64(col*scale+gutter), float64(row*scale+gutter), float64(scale), float64(scale))
+			dc.Fill()
+		}
+	}
+
+	// draw the walls and path markers on top of the colors
+	drawLines(dc, lines, PNGOptions{}.withDefaults())
+
+	// write the image as PNG
+	err := dc.EncodePNG(w)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// toTrailPNG renders the grid as a PNG image file with a fading dot at the center of each cell in the trail.
+func (g *grid) toTrailPNG(w io.Writer, height, width, scale, gutter int, trail []CellInfo, lines []line) error {
+	dc := gg.NewContext(width, height)
+
+	// set the background of the image to white
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	// draw the walls and path markers
+	drawLines(dc, lines, PNGOptions{}.withDefaults())
+
+	// draw the trail from the newest cell to the oldest, skipping cells that have already been drawn.
+	// the opacity of the dot is proportional to its position in the trail.
+	drawn := make(map[CellInfo]bool)
+	for n := len(trail) - 1; n >= 0; n-- {
+		ci := trail[n]
+		if drawn[ci] {
+			continue
+		}
+		drawn[ci] = true
+		cx, cy := float64(ci.Col*scale+scale/2+gutter), float64(ci.Row*scale+scale/2+gutter)
+		dc.SetRGBA(0, 0, 1, float64(n+1)/float64(len(trail)))
+		dc.DrawCircle(cx, cy, float64(scale)/4)
+		dc.Fill()
+	}
+
+	// write the image as PNG
+	err := dc.EncodePNG(w)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// drawLines draws walls and path markers using the colors, line width, and caps from the options.
+func drawLines(dc *gg.Context, lines []line, opts PNGOptions) {
+	// gg uses round caps unless it is told otherwise
+	if opts.RoundedCaps {
+		dc.SetLineCapRound()
+	} else {
+		dc.SetLineCapSquare()
+	}
+
+	// draw walls using the wall color, with the border walls in their own width
+	dc.SetColor(opts.Wall)
 	for _, l := range lines {
-		if !l.onPath {
+		if l.isWall() {
+			if l.border {
+				dc.SetLineWidth(opts.BorderLineWidth)
+			} else {
+				dc.SetLineWidth(opts.LineWidth)
+			}
 			dc.DrawLine(l.from.x, l.from.y, l.to.x, l.to.y)
 			dc.Stroke()
 		}
 	}
 
-	// draw path markers as red lines, 3 pixels wide
-	dc.SetRGB(1, 0, 0)
-	dc.SetLineWidth(3)
+	// draw path markers using the path color
+	dc.SetColor(opts.Path)
+	dc.SetLineWidth(opts.LineWidth)
 	for _, l := range lines {
 		if l.onPath {
 			dc.DrawLine(l.from.x, l.from.y, l.to.x, l.to.y)
@@ -121,22 +769,91 @@ func (g *grid) toPNG(w io.Writer, height, width int, lines []line) error {
 		}
 	}
 
-	// write the image as PNG
-	err := dc.EncodePNG(w)
-	if err != nil {
-		return err
+	// draw the entrance and exit markers using their colors
+	dc.SetColor(opts.Entrance)
+	for _, l := range lines {
+		if l.entrance {
+			dc.DrawLine(l.from.x, l.from.y, l.to.x, l.to.y)
+			dc.Stroke()
+		}
+	}
+	dc.SetColor(opts.Exit)
+	for _, l := range lines {
+		if l.exit {
+			dc.DrawLine(l.from.x, l.from.y, l.to.x, l.to.y)
+			dc.Stroke()
+		}
 	}
+}
 
-	return nil
+// normalizeWeights validates that the weights match the dimensions of the grid
+// and returns a copy of them scaled to the range [0, 1].
+// if all the weights are the same, the normalized weights are all zero.
+func (g *grid) normalizeWeights(weights [][]float64) ([][]float64, error) {
+	if weights == nil {
+		return nil, fmt.Errorf("weights: missing")
+	} else if len(weights) != g.height {
+		return nil, fmt.Errorf("weights: want %d rows, got %d", g.height, len(weights))
+	}
+	for row := range weights {
+		if len(weights[row]) != g.width {
+			return nil, fmt.Errorf("weights: row %d: want %d columns, got %d", row, g.width, len(weights[row]))
+		}
+	}
+
+	// find the range of the weights
+	lo, hi := weights[0][0], weights[0][0]
+	for row := range weights {
+		for _, weight := range weights[row] {
+			lo, hi = min(lo, weight), max(hi, weight)
+		}
+	}
+
+	// scale the weights into the range [0, 1]
+	shades := make([][]float64, g.height)
+	for row := range weights {
+		shades[row] = make([]float64, g.width)
+		if hi == lo {
+			continue
+		}
+		for col, weight := range weights[row] {
+			shades[row][col] = (weight - lo) / (hi - lo)
+		}
+	}
+
+	return shades, nil
 }
 
 // toSVG renders the grid as an SVG.
-func (g *grid) toSVG(w io.Writer, height, width int, lines []line) error {
+func (g *grid) toSVG(w io.Writer, height, width int, lines []line, arrows []arrow, opts SVGOptions) error {
+	var class []string
+	if opts.Class != "" {
+		class = append(class, `class="`+html.EscapeString(opts.Class)+`"`)
+	}
 	canvas := svgo.New(w)
 	canvas.Start(width, height)
-	canvas.Rect(0, 0, width, height, "fill:white")
+	canvas.Rect(0, 0, width, height, opts.BackgroundStyle)
 	for _, l := range lines {
-		canvas.Line(int(l.from.x), int(l.from.y), int(l.to.x), int(l.to.y), "stroke:black")
+		style := opts.WallStyle
+		if l.onPath {
+			style = opts.PathStyle
+		} else if l.entrance {
+			style = opts.EntranceStyle
+		} else if l.exit {
+			style = opts.ExitStyle
+		}
+		canvas.Line(int(l.from.x), int(l.from.y), int(l.to.x), int(l.to.y), append([]string{style}, class...)...)
+	}
+	for _, a := range arrows {
+		style := opts.ExitArrowStyle
+		if a.entrance {
+			style = opts.EntranceArrowStyle
+		}
+		var xs, ys []int
+		for _, p := range a.points {
+			xs, ys = append(xs, int(math.Round(p.x))), append(ys, int(math.Round(p.y)))
+		}
+		canvas.Polygon(xs, ys, append([]string{style}, class...)...)
 	}
 	canvas.End()
 	return nil
@@ -144,6 +861,170 @@ func (g *grid) toSVG(w io.Writer, height, width int, lines []line) error {
 
 // toText renders the grid using IBM box glyphs
 func (g *grid) toText(w io.Writer) error {
+	return writeRunes(w, g.toRunes())
+}
+
+// toTextStream renders the grid using IBM box glyphs, writing each line as soon as it is built.
+// it derives every glyph from its position and the neighboring cells, so the output matches toText.
+func (g *grid) toTextStream(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	// isMasked returns true if the cell is masked or outside the grid
+	isMasked := func(row, col int) bool {
+		return !g.inBounds(row, col) || g.cells[row][col].masked
+	}
+
+	// each row of cells produces the line along its northern walls and the line through its centers.
+	// the extra row at the end produces the line along the southern walls of the last row.
+	for row := 0; row <= g.height; row++ {
+		for col := 0; col <= g.width; col++ {
+			// the corner to the northwest of the cell
+			glyph := ' '
+			if !(isMasked(row-1, col-1) && isMasked(row-1, col) && isMasked(row, col-1) && isMasked(row, col)) {
+				isTop, isBottom, isLeft, isRight := row == 0, row == g.height, col == 0, col == g.width
+				switch {
+				case isTop && isLeft:
+					glyph = '╔'
+				case isTop && isRight:
+					glyph = '╗'
+				case isBottom && isLeft:
+					glyph = '╚'
+				case isBottom && isRight:
+					glyph = '╝'
+				case isTop:
+					glyph = '╦'
+				case isBottom:
+					glyph = '╩'
+				case isLeft:
+					glyph = '╠'
+				case isRight:
+					glyph = '╣'
+				default:
+					glyph = '╬'
+				}
+			}
+			bw.WriteRune(glyph)
+			if col == g.width {
+				break
+			}
+			// the wall to the north of the cell
+			glyph = ' '
+			if !(isMasked(row-1, col) && isMasked(row, col)) {
+				var wall bool
+				if row < g.height {
+					wall = g.cells[row][col].walls.north
+				} else {
+					wall = g.cells[row-1][col].walls.south
+				}
+				if wall {
+					glyph = '═'
+				}
+			}
+			bw.WriteRune(glyph)
+		}
+		bw.WriteByte('\n')
+		if row == g.height {
+			break
+		}
+
+		for col := 0; col <= g.width; col++ {
+			// the wall to the west of the cell
+			glyph := ' '
+			if !(isMasked(row, col-1) && isMasked(row, col)) {
+				var wall bool
+				if col < g.width {
+					wall = g.cells[row][col].walls.west
+				} else {
+					wall = g.cells[row][col-1].walls.east
+				}
+				if wall {
+					glyph = '║'
+				}
+			}
+			bw.WriteRune(glyph)
+			if col == g.width {
+				break
+			}
+			// the center of the cell
+			c := g.cells[row][col]
+			switch {
+			case c.masked:
+				glyph = ' '
+			case c.entrance:
+				glyph = 'E'
+			case c.exit:
+				glyph = 'X'
+			case c.onPath:
+				glyph = '*'
+			default:
+				glyph = ' '
+			}
+			bw.WriteRune(glyph)
+		}
+		bw.WriteByte('\n')
+	}
+	bw.WriteByte('\n')
+
+	return bw.Flush()
+}
+
+// toASCII renders the grid using plain ASCII characters.
+// corners are '+', horizontal walls are '-', and vertical walls are '|'.
+func (g *grid) toASCII(w io.Writer) error {
+	return g.toStyledText(w, ASCII())
+}
+
+// toStyledText renders the grid with the glyphs from the style.
+// it builds the maze with the double-line glyphs and then swaps each one for the style's glyph.
+func (g *grid) toStyledText(w io.Writer, style TextStyle) error {
+	glyphs := style.glyphs()
+	maze := g.toRunes()
+	for _, line := range maze {
+		for n, r := range line {
+			if glyph, ok := glyphs[r]; ok {
+				line[n] = glyph
+			}
+		}
+	}
+	return writeRunes(w, maze)
+}
+
+// toLabeledText renders the grid using IBM box glyphs, adding row and column numbers around the maze.
+func (g *grid) toLabeledText(w io.Writer) error {
+	maze := g.toRunes()
+
+	// the labels are as wide as the largest row or column number
+	rowDigits, colDigits := len(fmt.Sprint(g.height-1)), len(fmt.Sprint(g.width-1))
+
+	// build the column header. each digit of the column number gets its own line,
+	// and the digits sit over the center of the cell.
+	header := make([][]rune, colDigits)
+	for n := range header {
+		header[n] = []rune(fmt.Sprintf("%*s", rowDigits+len(maze[0]), ""))
+		for col := 0; col < g.width; col++ {
+			label := fmt.Sprintf("%*d", colDigits, col)
+			header[n][rowDigits+1+col*2+1] = rune(label[n])
+		}
+	}
+
+	// label the lines through the centers of the cells with the row number, and pad the others
+	var labeled [][]rune
+	labeled = append(labeled, header...)
+	for n, line := range maze {
+		label := fmt.Sprintf("%*s ", rowDigits, "")
+		if n%2 == 1 {
+			label = fmt.Sprintf("%*d ", rowDigits, n/2)
+		}
+		labeled = append(labeled, append([]rune(label), line...))
+	}
+	labeled = append(labeled, header...)
+
+	return writeRunes(w, labeled)
+}
+
+// toRunes renders the grid as rows of IBM box glyphs.
+// every cell is doubled so that walls and corners get their own rune.
+func (g *grid) toRunes() [][]rune {
 	// define constants for the edges of the maze
 	north, east, south, west := 0, g.width-1, g.height-1, 0
 
@@ -173,85 +1054,127 @@ func (g *grid) toText(w io.Writer) error {
 			// set the corners of the cell to the correct IBM box glyph
 			// start with the northwest corner of the cell
 			if isNorthEdge && isWestEdge {
-				glyph = '╔'
+				glyph = doubleLine.NorthWest
 			} else if isNorthEdge {
-				glyph = '╦'
+				glyph = doubleLine.TeeSouth
 			} else if isWestEdge {
-				glyph = '╠'
+				glyph = doubleLine.TeeEast
 			} else {
-				glyph = '╬'
+				glyph = doubleLine.Cross
 			}
 			maze[cRow-1][cCol-1] = glyph
 			// set the northern edge of the cell
 			if c.walls.north {
-				glyph = '═'
+				glyph = doubleLine.Horizontal
 			} else {
-				glyph = ' '
+				glyph = doubleLine.Space
 			}
 			maze[cRow-1][cCol] = glyph
 			// set the northeast corner of the cell
 			if isNorthEdge && isEastEdge {
-				glyph = '╗'
+				glyph = doubleLine.NorthEast
 			} else if isNorthEdge {
-				glyph = '╦'
+				glyph = doubleLine.TeeSouth
 			} else if isEastEdge {
-				glyph = '╣'
+				glyph = doubleLine.TeeWest
 			} else {
-				glyph = '╬'
+				glyph = doubleLine.Cross
 			}
 			maze[cRow-1][cCol+1] = glyph
 			// set the eastern edge of the cell
 			if c.walls.east {
-				glyph = '║'
+				glyph = doubleLine.Vertical
 			} else {
-				glyph = ' '
+				glyph = doubleLine.Space
 			}
 			maze[cRow][cCol+1] = glyph
 			// set the southeast corner of the cell
 			if isSouthEdge && isEastEdge {
-				glyph = '╝'
+				glyph = doubleLine.SouthEast
 			} else if isSouthEdge {
-				glyph = '╩'
+				glyph = doubleLine.TeeNorth
 			} else if isEastEdge {
-				glyph = '╣'
+				glyph = doubleLine.TeeWest
 			} else {
-				glyph = '╬'
+				glyph = doubleLine.Cross
 			}
 			maze[cRow+1][cCol+1] = glyph
 			// set the southern edge of the cell
 			if c.walls.south {
-				glyph = '═'
+				glyph = doubleLine.Horizontal
 			} else {
-				glyph = ' '
+				glyph = doubleLine.Space
 			}
 			maze[cRow+1][cCol] = glyph
 			// set the southwest corner of the cell
 			if isSouthEdge && isWestEdge {
-				glyph = '╚'
+				glyph = doubleLine.SouthWest
 			} else if isSouthEdge {
-				glyph = '╩'
+				glyph = doubleLine.TeeNorth
 			} else if isWestEdge {
-				glyph = '╠'
+				glyph = doubleLine.TeeEast
 			} else {
-				glyph = '╬'
+				glyph = doubleLine.Cross
 			}
 			maze[cRow+1][cCol-1] = glyph
 			// set the western edge of the cell
 			if c.walls.west {
-				glyph = '║'
+				glyph = doubleLine.Vertical
 			} else {
-				glyph = ' '
+				glyph = doubleLine.Space
 			}
 			maze[cRow][cCol-1] = glyph
 			// usually set the center of the cell to a space
-			if c.onPath {
+			if c.entrance {
+				maze[cRow][cCol] = 'E'
+			} else if c.exit {
+				maze[cRow][cCol] = 'X'
+			} else if c.onPath {
 				maze[cRow][cCol] = '*'
 			} else {
-				maze[cRow][cCol] = ' '
+				maze[cRow][cCol] = doubleLine.Space
 			}
 		}
 	}
 
+	// blank out masked cells, along with any walls and corners that only touch masked cells
+	isMasked := func(row, col int) bool {
+		return !g.inBounds(row, col) || g.cells[row][col].masked
+	}
+	for row := north; row <= south; row++ {
+		for col := west; col <= east; col++ {
+			if !g.cells[row][col].masked {
+				continue
+			}
+			cRow, cCol := row*2+1, col*2+1
+			maze[cRow][cCol] = doubleLine.Space
+			if isMasked(row-1, col) {
+				maze[cRow-1][cCol] = doubleLine.Space
+			}
+			if isMasked(row, col+1) {
+				maze[cRow][cCol+1] = doubleLine.Space
+			}
+			if isMasked(row+1, col) {
+				maze[cRow+1][cCol] = doubleLine.Space
+			}
+			if isMasked(row, col-1) {
+				maze[cRow][cCol-1] = doubleLine.Space
+			}
+			for _, dr := range []int{-1, 1} {
+				for _, dc := range []int{-1, 1} {
+					if isMasked(row+dr, col) && isMasked(row, col+dc) && isMasked(row+dr, col+dc) {
+						maze[cRow+dr][cCol+dc] = doubleLine.Space
+					}
+				}
+			}
+		}
+	}
+
+	return maze
+}
+
+// writeRunes writes each row of runes as a line of text, followed by a blank line.
+func writeRunes(w io.Writer, maze [][]rune) error {
 	// convert the runes in the maze to a slice of bytes
 	buffer := &bytes.Buffer{}
 	for _, line := range maze {
@@ -268,3 +1191,46 @@ func (g *grid) toText(w io.Writer) error {
 
 	return nil
 }
+
+// toIntGrid renders the grid as integers, using 0 for a path and 1 for a wall.
+// like toText, every cell is doubled so that walls and corners get their own element.
+func (g *grid) toIntGrid() [][]int {
+	// allocate memory for the maze, starting with every element as a wall
+	maze := make([][]int, g.height*2+1)
+	for row := 0; row < len(maze); row++ {
+		maze[row] = make([]int, g.width*2+1)
+		for n := range maze[row] {
+			maze[row][n] = 1
+		}
+	}
+
+	// now open the centers and walls based on each cell's attributes
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			c := g.cells[row][col]
+			if c.masked {
+				// masked cells are left as walls
+				continue
+			}
+
+			// derive the coordinates of the center of the cell in the maze array
+			cRow, cCol := row*2+1, col*2+1
+
+			maze[cRow][cCol] = 0
+			if !c.walls.north {
+				maze[cRow-1][cCol] = 0
+			}
+			if !c.walls.east {
+				maze[cRow][cCol+1] = 0
+			}
+			if !c.walls.south {
+				maze[cRow+1][cCol] = 0
+			}
+			if !c.walls.west {
+				maze[cRow][cCol-1] = 0
+			}
+		}
+	}
+
+	return maze
+}
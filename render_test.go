@@ -0,0 +1,972 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// nopCloser adds a Close method to a writer.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// heapProbe is a writer that records the most heap in use the first time each strip writes to it,
+// while the strip's image is still live.
+type heapProbe struct {
+	peak  *uint64
+	wrote bool
+}
+
+func (p *heapProbe) Write(b []byte) (int, error) {
+	if !p.wrote {
+		p.wrote = true
+		var ms runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&ms)
+		*p.peak = max(*p.peak, ms.HeapAlloc)
+	}
+	return len(b), nil
+}
+
+func (p *heapProbe) Close() error { return nil }
+
+func TestRenderPNGStripsStack(t *testing.T) {
+	r, err := RectangleMazeWith(9, 7, WilsonGenerator{}, true, WithSeed(11))
+	if err != nil {
+		t.Fatalf("RectangleMaze: %v", err)
+	}
+	const scale = 20
+	want := r.RenderImage(scale)
+
+	// stack the strips into one image, which should be the same as the full image
+	got := image.NewRGBA(want.Bounds())
+	y := 0
+	var strips []*bytes.Buffer
+	err = r.RenderPNGStrips(scale, 4, func(strip int) (io.WriteCloser, error) {
+		strips = append(strips, &bytes.Buffer{})
+		return nopCloser{strips[strip]}, nil
+	})
+	if err != nil {
+		t.Fatalf("RenderPNGStrips: %v", err)
+	}
+	if len(strips) != 3 {
+		t.Fatalf("RenderPNGStrips: want 3 strips, got %d", len(strips))
+	}
+	for n, b := range strips {
+		img, err := png.Decode(b)
+		if err != nil {
+			t.Fatalf("strip %d: %v", n, err)
+		}
+		bounds := img.Bounds()
+		if bounds.Dx() != want.Bounds().Dx() {
+			t.Fatalf("strip %d: width: want %d, got %d", n, want.Bounds().Dx(), bounds.Dx())
+		}
+		draw.Draw(got, image.Rect(0, y, bounds.Dx(), y+bounds.Dy()), img, bounds.Min, draw.Src)
+		y += bounds.Dy()
+	}
+	if y != want.Bounds().Dy() {
+		t.Fatalf("strips: height: want %d, got %d", want.Bounds().Dy(), y)
+	}
+	// the rasterizer rounds the anti-aliased edges of walls on the cuts a little differently,
+	// so allow the pixels to be off by a few levels
+	for i := range want.Pix {
+		if d := int(got.Pix[i]) - int(want.Pix[i]); d < -4 || d > 4 {
+			y, x := i/want.Stride, (i%want.Stride)/4
+			t.Fatalf("strips: pixel (%d, %d): stacked strips differ from RenderImage: want %d, got %d", x, y, want.Pix[i], got.Pix[i])
+		}
+	}
+}
+
+func TestRenderPNGStripsMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("renders a large maze")
+	}
+	r, err := RectangleMazeWith(250, 250, WilsonGenerator{}, false, WithSeed(3))
+	if err != nil {
+		t.Fatalf("RectangleMaze: %v", err)
+	}
+	const scale, rowsPerStrip = 10, 10
+	// the full image would be 2510 x 2510 pixels at 4 bytes each, about 25MB
+	full := uint64(250*scale+scale) * uint64(250*scale+scale) * 4
+
+	var base, peak uint64
+	var ms runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&ms)
+	base = ms.HeapAlloc
+	err = r.RenderPNGStrips(scale, rowsPerStrip, func(strip int) (io.WriteCloser, error) {
+		return &heapProbe{peak: &peak}, nil
+	})
+	if err != nil {
+		t.Fatalf("RenderPNGStrips: %v", err)
+	}
+	// each strip is about 1MB; allow plenty of room for the encoder and the line lists
+	if budget := base + full/4; peak > budget {
+		t.Errorf("RenderPNGStrips: heap in use: want at most %d bytes, got %d", budget, peak)
+	}
+}
+
+func TestRenderPNGStripsErrors(t *testing.T) {
+	r := loopMaze(t)
+	create := func(strip int) (io.WriteCloser, error) { return nopCloser{io.Discard}, nil }
+	if err := r.RenderPNGStrips(10, 0, create); err == nil {
+		t.Errorf("RenderPNGStrips: 0 rows per strip: want error, got nil")
+	}
+}
+
+func TestRenderTextPathMarkers(t *testing.T) {
+	r := loopMaze(t)
+	if got := strings.Count(renderText(t, r), "*"); got != 0 {
+		t.Errorf("RenderText: unsolved: want 0 markers, got %d", got)
+	}
+
+	// the entrance and exit are on the path but are marked with their own letters
+	for _, tc := range []struct {
+		name  string
+		solve func() error
+		want  int
+	}{
+		{"Solve", r.Solve, 5},
+		{"SolveBFS", r.SolveBFS, 1},
+	} {
+		if err := tc.solve(); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		text := renderText(t, r)
+		if got := strings.Count(text, "*"); got != tc.want || got != len(r.SolutionPath())-2 {
+			t.Errorf("%s: RenderText: want %d markers, got %d:\n%s", tc.name, tc.want, got, text)
+		}
+		if strings.Count(text, "E") != 1 || strings.Count(text, "X") != 1 {
+			t.Errorf("%s: RenderText: want one entrance and one exit:\n%s", tc.name, text)
+		}
+	}
+}
+
+// wallRuns scans a row of the image from left to right and returns the center and the width of each wall
+// that it crosses. the width is measured in ink, so a wall whose edges are anti-aliased across two pixels
+// still measures its true width.
+func wallRuns(img image.Image, y int) (centers, widths []float64) {
+	bounds := img.Bounds()
+	ink, weighted := 0.0, 0.0
+	for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+		dark := 0.0
+		if x < bounds.Max.X {
+			r, _, _, _ := img.At(x, y).RGBA()
+			dark = 1 - float64(r)/0xffff
+		}
+		if dark > 0.01 {
+			ink, weighted = ink+dark, weighted+dark*(float64(x)+0.5)
+		} else if ink > 0 {
+			centers, widths = append(centers, weighted/ink), append(widths, ink)
+			ink, weighted = 0, 0
+		}
+	}
+	return centers, widths
+}
+
+func TestRenderPNGCorridorRatio(t *testing.T) {
+	r := loopMaze(t)
+	render := func(scale int, opts PNGOptions) image.Image {
+		t.Helper()
+		var b bytes.Buffer
+		if err := r.RenderPNGWithOptions(&b, scale, opts); err != nil {
+			t.Fatalf("RenderPNGWithOptions: %v", err)
+		}
+		img, err := png.Decode(&b)
+		if err != nil {
+			t.Fatalf("png: %v", err)
+		}
+		return img
+	}
+
+	// the walls between cells are drawn by both cells, which darkens the anti-aliased edges of walls
+	// that don't fill whole pixels, so the widths are even to keep the walls on pixel boundaries
+	for _, tc := range []struct {
+		lineWidth, ratio float64
+	}{
+		{2, 20},
+		{2, 9},
+		{4, 4},
+	} {
+		opts := PNGOptions{LineWidth: tc.lineWidth, CorridorRatio: tc.ratio}
+		// the ratio sets the size of the cells, so the scale makes no difference
+		small, large := render(10, opts), render(100, opts)
+		if small.Bounds() != large.Bounds() {
+			t.Fatalf("ratio %g: bounds: scale 10 gave %v, scale 100 gave %v", tc.ratio, small.Bounds(), large.Bounds())
+		}
+
+		// scan through the middle of the second row, which has walls on the west and east borders
+		// and between the second and third cells
+		cell := tc.lineWidth * (tc.ratio + 1)
+		y := int(cell/2) + int(cell) + int(cell)/2
+		centers, widths := wallRuns(small, y)
+		if len(centers) != 3 {
+			t.Fatalf("ratio %g: walls: want 3, got %d at %v", tc.ratio, len(centers), centers)
+		}
+		for n, width := range widths {
+			if math.Abs(width-tc.lineWidth) > 0.1 {
+				t.Errorf("ratio %g: wall %d: width: want %g, got %.2f", tc.ratio, n, tc.lineWidth, width)
+			}
+		}
+		// the first passage is two cells wide, since the wall between the first and second cells is open
+		for n, cells := range []float64{2, 1} {
+			passage := centers[n+1] - centers[n] - tc.lineWidth
+			if want := cells*cell - tc.lineWidth; math.Abs(passage-want) > 0.1 {
+				t.Errorf("ratio %g: passage %d: width: want %g, got %.2f", tc.ratio, n, want, passage)
+			}
+		}
+	}
+}
+
+func TestRenderSVGCorridorRatio(t *testing.T) {
+	r := loopMaze(t)
+	render := func(scale int) string {
+		t.Helper()
+		var b bytes.Buffer
+		if err := r.RenderSVGWithOptions(&b, scale, SVGOptions{LineWidth: 1, CorridorRatio: 40}); err != nil {
+			t.Fatalf("RenderSVGWithOptions: %v", err)
+		}
+		return b.String()
+	}
+	small := render(10)
+	if large := render(100); small != large {
+		t.Errorf("RenderSVGWithOptions: scale 10 and scale 100 gave different images")
+	}
+	if !strings.Contains(small, "stroke-width:1") {
+		t.Errorf("RenderSVGWithOptions: want stroke-width:1 in the wall style")
+	}
+	// three cells of 41 pixels and a margin of 20 on each side
+	if !strings.Contains(small, `width="163"`) {
+		t.Errorf("RenderSVGWithOptions: want a width of 163 pixels:\n%s", small[:min(len(small), 200)])
+	}
+}
+
+func TestRenderPNGWithOptionsColors(t *testing.T) {
+	r := loopMaze(t)
+	background, wall := color.RGBA{R: 10, G: 20, B: 30, A: 255}, color.RGBA{R: 200, G: 100, B: 50, A: 255}
+	var b bytes.Buffer
+	if err := r.RenderPNGWithOptions(&b, 20, PNGOptions{Background: background, Wall: wall}); err != nil {
+		t.Fatalf("RenderPNGWithOptions: %v", err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	same := func(got color.Color, want color.RGBA) bool {
+		return color.RGBAModel.Convert(got).(color.RGBA) == want
+	}
+	// the corner of the image is in the margin
+	if got := img.At(0, 0); !same(got, background) {
+		t.Errorf("background: want %v, got %v", background, got)
+	}
+	// the western border runs down the edge of the margin, through the middle of the second row
+	if got := img.At(10, 40); !same(got, wall) {
+		t.Errorf("wall: want %v, got %v", wall, got)
+	}
+	// the center of a cell off the path is open
+	if got := img.At(40, 60); !same(got, background) {
+		t.Errorf("cell: want %v, got %v", background, got)
+	}
+}
+
+func TestToGrid(t *testing.T) {
+	// the entrance opens to the north and the exit to the east
+	want := [][]int{
+		{1, 0, 1, 1, 1, 1, 1},
+		{1, 0, 0, 0, 0, 0, 0},
+		{1, 0, 1, 1, 1, 0, 1},
+		{1, 0, 0, 0, 1, 0, 1},
+		{1, 0, 1, 1, 1, 0, 1},
+		{1, 0, 0, 0, 0, 0, 1},
+		{1, 1, 1, 1, 1, 1, 1},
+	}
+	got := loopMaze(t).ToGrid()
+	if len(got) != len(want) {
+		t.Fatalf("ToGrid: want %d rows, got %d", len(want), len(got))
+	}
+	for row := range want {
+		for col := range want[row] {
+			if got[row][col] != want[row][col] {
+				t.Fatalf("ToGrid: row %d: want %v, got %v", row, want[row], got[row])
+			}
+		}
+	}
+}
+
+func TestRenderGateMarkers(t *testing.T) {
+	r := loopMaze(t)
+	lines := strings.Split(renderText(t, r), "\n")
+	// the entrance is at the north-west corner and the exit at the north-east corner
+	if got := []rune(lines[1]); got[1] != 'E' || got[5] != 'X' {
+		t.Errorf("RenderText: want E and X in the first row of cells, got %q", lines[1])
+	}
+
+	var b bytes.Buffer
+	if err := r.RenderPNG(&b, 20); err != nil {
+		t.Fatalf("RenderPNG: %v", err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	opts := PNGOptions{}.withDefaults()
+	// marked returns true if any pixel in the cell is the marker color
+	marked := func(row, col int, marker color.Color) bool {
+		want := color.RGBAModel.Convert(marker)
+		for y := 10 + row*20; y < 30+row*20; y++ {
+			for x := 10 + col*20; x < 30+col*20; x++ {
+				if color.RGBAModel.Convert(img.At(x, y)) == want {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if !marked(0, 0, opts.Entrance) || marked(0, 0, opts.Exit) {
+		t.Errorf("RenderPNG: want the entrance marked in the entrance color")
+	}
+	if !marked(0, 2, opts.Exit) || marked(0, 2, opts.Entrance) {
+		t.Errorf("RenderPNG: want the exit marked in the exit color")
+	}
+	if marked(1, 1, opts.Entrance) || marked(1, 1, opts.Exit) {
+		t.Errorf("RenderPNG: want no marker in the center cell")
+	}
+
+	b.Reset()
+	if err := r.RenderSVG(&b, 20); err != nil {
+		t.Fatalf("RenderSVG: %v", err)
+	}
+	if svg := b.String(); !strings.Contains(svg, "stroke:green") || !strings.Contains(svg, "stroke:blue") {
+		t.Errorf("RenderSVG: want the entrance and exit markers")
+	}
+}
+
+func TestRenderImage(t *testing.T) {
+	r, err := RectangleMazeWith(5, 8, WilsonGenerator{}, true, WithSeed(2))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	img := r.RenderImage(20)
+	// eight cells across and five down, with a margin of half the scale on every side
+	if want := image.Rect(0, 0, 8*20+20, 5*20+20); img.Bounds() != want {
+		t.Errorf("RenderImage: bounds: want %v, got %v", want, img.Bounds())
+	}
+
+	// RenderPNG encodes the same image
+	var b bytes.Buffer
+	if err := r.RenderPNG(&b, 20); err != nil {
+		t.Fatalf("RenderPNG: %v", err)
+	}
+	decoded, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	want := image.NewRGBA(decoded.Bounds())
+	draw.Draw(want, want.Bounds(), decoded, decoded.Bounds().Min, draw.Src)
+	if !bytes.Equal(img.Pix, want.Pix) {
+		t.Errorf("RenderImage: image differs from RenderPNG")
+	}
+}
+
+func TestRenderBlockPNG(t *testing.T) {
+	r := loopMaze(t)
+	const cellPx, wallPx = 10, 2
+	var b bytes.Buffer
+	if err := r.RenderBlockPNG(&b, cellPx, wallPx); err != nil {
+		t.Fatalf("RenderBlockPNG: %v", err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	// four rows and columns of walls and three of cells
+	if want := image.Rect(0, 0, 4*wallPx+3*cellPx, 4*wallPx+3*cellPx); img.Bounds() != want {
+		t.Fatalf("RenderBlockPNG: bounds: want %v, got %v", want, img.Bounds())
+	}
+
+	// center returns the pixel in the middle of the block for an element of the int grid
+	center := func(n int) int {
+		return n/2*(wallPx+cellPx) + n%2*wallPx + min(wallPx, cellPx)/2
+	}
+	for row, elements := range r.ToGrid() {
+		for col, element := range elements {
+			want := color.RGBAModel.Convert(color.White)
+			if element == 1 {
+				want = color.RGBAModel.Convert(color.Black)
+			}
+			if got := color.RGBAModel.Convert(img.At(center(col), center(row))); got != want {
+				t.Errorf("RenderBlockPNG: element (%d, %d): want %v, got %v", row, col, want, got)
+			}
+		}
+	}
+
+	if err := r.RenderBlockPNG(&b, 0, wallPx); err == nil {
+		t.Errorf("RenderBlockPNG: cell size 0: want error, got nil")
+	}
+	if err := r.RenderBlockPNG(&b, cellPx, 0); err == nil {
+		t.Errorf("RenderBlockPNG: wall size 0: want error, got nil")
+	}
+}
+
+func TestRenderPNGBorderLineWidth(t *testing.T) {
+	r := loopMaze(t)
+	var b bytes.Buffer
+	if err := r.RenderPNGWithOptions(&b, 40, PNGOptions{LineWidth: 2, BorderLineWidth: 8}); err != nil {
+		t.Fatalf("RenderPNGWithOptions: %v", err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+
+	// scan through the middle of the second row, which has walls on the west and east borders
+	// and between the second and third cells
+	centers, widths := wallRuns(img, 20+40+20)
+	if len(centers) != 3 {
+		t.Fatalf("walls: want 3, got %d at %v", len(centers), centers)
+	}
+	for n, want := range []float64{8, 2, 8} {
+		if math.Abs(widths[n]-want) > 0.1 {
+			t.Errorf("wall %d: width: want %g, got %.2f", n, want, widths[n])
+		}
+	}
+	// the thick border is centered on the edge of the maze, so it doesn't move the walls
+	for n, want := range []float64{20, 100, 140} {
+		if math.Abs(centers[n]-want) > 0.1 {
+			t.Errorf("wall %d: center: want %g, got %.2f", n, want, centers[n])
+		}
+	}
+}
+
+func TestRenderSVGArrows(t *testing.T) {
+	r := loopMaze(t)
+	render := func(opts SVGOptions) string {
+		t.Helper()
+		var b bytes.Buffer
+		if err := r.RenderSVGWithOptions(&b, 20, opts); err != nil {
+			t.Fatalf("RenderSVGWithOptions: %v", err)
+		}
+		return b.String()
+	}
+
+	// with a scale of 20 the gutter is 10 and the arrows are 8 pixels long.
+	// the entrance arrow points south into the northern opening of the first cell and
+	// the exit arrow points east out of the eastern opening of the third cell.
+	svg := render(SVGOptions{})
+	if got := strings.Count(svg, "<polygon"); got != 2 {
+		t.Fatalf("RenderSVG: want 2 arrows, got %d:\n%s", got, svg)
+	}
+	for _, want := range []string{
+		`<polygon points="20,10 24,2 16,2" style="fill:green" />`,
+		`<polygon points="78,20 70,24 70,16" style="fill:blue" />`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("RenderSVG: want %s in\n%s", want, svg)
+		}
+	}
+
+	svg = render(SVGOptions{EntranceArrowStyle: "fill:orange", ExitArrowStyle: "fill:purple"})
+	if !strings.Contains(svg, `style="fill:orange"`) || !strings.Contains(svg, `style="fill:purple"`) {
+		t.Errorf("RenderSVG: want the arrow styles from the options:\n%s", svg)
+	}
+	if svg = render(SVGOptions{NoArrows: true}); strings.Contains(svg, "<polygon") {
+		t.Errorf("RenderSVG: NoArrows: want no arrows:\n%s", svg)
+	}
+}
+
+func TestRenderHeatmapPNG(t *testing.T) {
+	render := func(r *Rectangle) image.Image {
+		t.Helper()
+		var b bytes.Buffer
+		if err := r.RenderHeatmapPNG(&b, 20); err != nil {
+			t.Fatalf("RenderHeatmapPNG: %v", err)
+		}
+		img, err := png.Decode(&b)
+		if err != nil {
+			t.Fatalf("png: %v", err)
+		}
+		return img
+	}
+	// center returns the color at the center of the cell, with a scale of 20 and a gutter of 10
+	center := func(img image.Image, row, col int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(col*20+20, row*20+20)).(color.RGBA)
+	}
+
+	// the entrance is in the north-west corner and the farthest cell, 4 steps away, is in the south-east corner
+	img := render(loopMaze(t))
+	for _, tc := range []struct {
+		row, col int
+		want     color.RGBA
+	}{
+		{0, 0, color.RGBA{B: 255, A: 255}},
+		{1, 1, color.RGBA{R: 128, B: 128, A: 255}},
+		{2, 2, color.RGBA{R: 255, A: 255}},
+	} {
+		got := center(img, tc.row, tc.col)
+		if got.G != 0 || abs(int(got.R)-int(tc.want.R)) > 1 || abs(int(got.B)-int(tc.want.B)) > 1 {
+			t.Errorf("RenderHeatmapPNG: (%d, %d): want %v, got %v", tc.row, tc.col, tc.want, got)
+		}
+	}
+	if center(img, 0, 0) == center(img, 2, 2) {
+		t.Errorf("RenderHeatmapPNG: want the entrance and the farthest cell in different colors")
+	}
+
+	// the exit is walled off from the entrance, so it is left white
+	r := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 1},
+		passage{{0, 0}, {0, 1}}, passage{{0, 0}, {1, 0}},
+	)
+	if got, want := center(render(r), 1, 1), (color.RGBA{R: 255, G: 255, B: 255, A: 255}); got != want {
+		t.Errorf("RenderHeatmapPNG: unreachable: want %v, got %v", want, got)
+	}
+}
+
+func TestRenderTextStream(t *testing.T) {
+	// a mask with a hole in the middle and two corners cut off
+	mask := make([][]bool, 6)
+	for row := range mask {
+		mask[row] = []bool{true, true, true, true, true, true, true, true}
+	}
+	for _, off := range [][2]int{{0, 7}, {5, 0}, {2, 3}, {2, 4}, {3, 3}, {3, 4}} {
+		mask[off[0]][off[1]] = false
+	}
+
+	for _, tc := range []struct {
+		name  string
+		new   func(seed int64) (*Rectangle, error)
+		solve bool
+	}{
+		{"unsolved", func(seed int64) (*Rectangle, error) {
+			return RectangleMazeWith(7, 9, WilsonGenerator{}, false, WithSeed(seed))
+		}, false},
+		{"solved", func(seed int64) (*Rectangle, error) {
+			return RectangleMazeWith(7, 9, WilsonGenerator{}, false, WithSeed(seed))
+		}, true},
+		{"masked", func(seed int64) (*Rectangle, error) {
+			return RectangleMazeWith(6, 8, WilsonGenerator{}, false, withMask(mask), WithSeed(seed))
+		}, false},
+		{"masked and solved", func(seed int64) (*Rectangle, error) {
+			return RectangleMazeWith(6, 8, WilsonGenerator{}, false, withMask(mask), WithSeed(seed))
+		}, true},
+	} {
+		for seed := int64(1); seed <= 5; seed++ {
+			r, err := tc.new(seed)
+			if err != nil {
+				t.Fatalf("%s: RectangleMazeWith: %v", tc.name, err)
+			}
+			if tc.solve {
+				if err := r.Solve(); err != nil {
+					t.Fatalf("%s: Solve: %v", tc.name, err)
+				}
+			}
+			var want, got bytes.Buffer
+			if err := r.RenderText(&want); err != nil {
+				t.Fatalf("%s: RenderText: %v", tc.name, err)
+			}
+			if err := r.RenderTextStream(&got); err != nil {
+				t.Fatalf("%s: RenderTextStream: %v", tc.name, err)
+			}
+			if !bytes.Equal(got.Bytes(), want.Bytes()) {
+				t.Errorf("%s: seed %d: RenderTextStream: want\n%s\ngot\n%s", tc.name, seed, want.String(), got.String())
+			}
+		}
+	}
+}
+
+func TestRenderPNGCaps(t *testing.T) {
+	r := loopMaze(t)
+	render := func(opts PNGOptions) image.Image {
+		t.Helper()
+		var b bytes.Buffer
+		if err := r.RenderPNGWithOptions(&b, 40, opts); err != nil {
+			t.Fatalf("RenderPNGWithOptions: %v", err)
+		}
+		img, err := png.Decode(&b)
+		if err != nil {
+			t.Fatalf("png: %v", err)
+		}
+		return img
+	}
+	dark := func(c color.Color) bool {
+		r, _, _, _ := c.RGBA()
+		return r < 0x8000
+	}
+
+	// the north-west corner of the maze is at (20, 20) and the walls are 12 pixels wide.
+	// the pixel 5 pixels out on both axes is inside a square cap but more than 6 pixels from the corner,
+	// so it is outside a round cap.
+	if got := render(PNGOptions{LineWidth: 12}).At(15, 15); !dark(got) {
+		t.Errorf("square caps: corner: want wall, got %v", got)
+	}
+	if got := render(PNGOptions{LineWidth: 12, RoundedCaps: true}).At(15, 15); dark(got) {
+		t.Errorf("RoundedCaps: corner: want background, got %v", got)
+	}
+	// both caps reach the edge of the wall along each side
+	for _, rounded := range []bool{false, true} {
+		if got := render(PNGOptions{LineWidth: 12, RoundedCaps: rounded}).At(20, 15); !dark(got) {
+			t.Errorf("RoundedCaps %v: edge: want wall, got %v", rounded, got)
+		}
+	}
+}
+
+func TestRenderPNGNoAntiAlias(t *testing.T) {
+	r := loopMaze(t)
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	// colors returns the number of different colors in the image
+	colors := func(opts PNGOptions) int {
+		t.Helper()
+		var b bytes.Buffer
+		if err := r.RenderPNGWithOptions(&b, 25, opts); err != nil {
+			t.Fatalf("RenderPNGWithOptions: %v", err)
+		}
+		img, err := png.Decode(&b)
+		if err != nil {
+			t.Fatalf("png: %v", err)
+		}
+		seen := map[color.RGBA]bool{}
+		bounds := img.Bounds()
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				seen[color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)] = true
+			}
+		}
+		return len(seen)
+	}
+	// the odd line width puts the edges of the walls in the middle of pixels, which anti-aliasing shades
+	if got := colors(PNGOptions{LineWidth: 3}); got <= 5 {
+		t.Errorf("anti-aliased: want more than the 5 option colors, got %d", got)
+	}
+	// the background, walls, path, entrance, and exit
+	if got := colors(PNGOptions{LineWidth: 3, NoAntiAlias: true}); got > 5 {
+		t.Errorf("NoAntiAlias: want at most 5 colors, got %d", got)
+	}
+}
+
+func TestRenderWeightedPNG(t *testing.T) {
+	r := loopMaze(t)
+	render := func(weights [][]float64) []byte {
+		t.Helper()
+		var b bytes.Buffer
+		if err := r.RenderWeightedPNG(&b, weights, 20); err != nil {
+			t.Fatalf("RenderWeightedPNG: %v", err)
+		}
+		return b.Bytes()
+	}
+	weights := [][]float64{
+		{0, 5, 10},
+		{5, 5, 5},
+		{0, 0, 10},
+	}
+	data := render(weights)
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	// the lightest weight is left white, the heaviest is solid blue, and the middle one is half way between
+	for _, tc := range []struct {
+		row, col int
+		want     color.RGBA
+	}{
+		{0, 0, color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{0, 1, color.RGBA{R: 128, G: 128, B: 255, A: 255}},
+		{0, 2, color.RGBA{B: 255, A: 255}},
+		{2, 2, color.RGBA{B: 255, A: 255}},
+	} {
+		got := color.RGBAModel.Convert(img.At(tc.col*20+20, tc.row*20+20)).(color.RGBA)
+		if abs(int(got.R)-int(tc.want.R)) > 1 || abs(int(got.G)-int(tc.want.G)) > 1 || got.B != tc.want.B {
+			t.Errorf("RenderWeightedPNG: (%d, %d): want %v, got %v", tc.row, tc.col, tc.want, got)
+		}
+	}
+
+	// the weights are normalized, so scaling and shifting them gives the same image
+	scaled := make([][]float64, len(weights))
+	for row := range weights {
+		for _, weight := range weights[row] {
+			scaled[row] = append(scaled[row], weight*7-3)
+		}
+	}
+	if !bytes.Equal(render(scaled), data) {
+		t.Errorf("RenderWeightedPNG: scaled weights gave a different image")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		weights [][]float64
+	}{
+		{"nil", nil},
+		{"rows", weights[:2]},
+		{"columns", [][]float64{{0, 1, 2}, {0, 1}, {0, 1, 2}}},
+	} {
+		if err := r.RenderWeightedPNG(io.Discard, tc.weights, 20); err == nil {
+			t.Errorf("RenderWeightedPNG: %s: want error, got nil", tc.name)
+		}
+	}
+}
+
+func TestRenderTrailPNG(t *testing.T) {
+	r := loopMaze(t)
+	// the trail goes back to the middle of the northern row, so that cell is drawn for its last visit
+	trail := []CellInfo{{0, 0}, {0, 1}, {0, 2}, {0, 1}}
+	var b bytes.Buffer
+	if err := r.RenderTrailPNG(&b, trail, 20); err != nil {
+		t.Fatalf("RenderTrailPNG: %v", err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	// the dots are blue with an opacity of their position in the trail, so the red channel shows how faint they are
+	for _, tc := range []struct {
+		row, col int
+		want     uint8
+	}{
+		{0, 0, 191},
+		{0, 2, 64},
+		{0, 1, 0},
+		{1, 1, 255},
+	} {
+		got := color.RGBAModel.Convert(img.At(tc.col*20+20, tc.row*20+20)).(color.RGBA)
+		if abs(int(got.R)-int(tc.want)) > 1 || got.G != got.R || got.B != 255 {
+			t.Errorf("RenderTrailPNG: (%d, %d): want red and green %d, got %v", tc.row, tc.col, tc.want, got)
+		}
+	}
+
+	for _, bad := range []CellInfo{{3, 0}, {0, 3}, {-1, 0}} {
+		if err := r.RenderTrailPNG(io.Discard, []CellInfo{{0, 0}, bad}, 20); err == nil {
+			t.Errorf("RenderTrailPNG: %v: want error, got nil", bad)
+		}
+	}
+}
+
+func TestRenderTextASCII(t *testing.T) {
+	var b bytes.Buffer
+	if err := loopMaze(t).RenderTextASCII(&b); err != nil {
+		t.Fatalf("RenderTextASCII: %v", err)
+	}
+	want := "+ +-+-+\n" +
+		"|E   X \n" +
+		"+ +-+ +\n" +
+		"|   | |\n" +
+		"+ +-+ +\n" +
+		"|     |\n" +
+		"+-+-+-+\n" +
+		"\n"
+	if got := b.String(); got != want {
+		t.Errorf("RenderTextASCII: want\n%s\ngot\n%s", want, got)
+	}
+
+	// a generated maze has the same layout as RenderText, with nothing outside of ASCII
+	r, err := RectangleMazeWith(12, 11, WilsonGenerator{}, false, WithSeed(1))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	b.Reset()
+	if err := r.RenderTextASCII(&b); err != nil {
+		t.Fatalf("RenderTextASCII: %v", err)
+	}
+	ascii, text := strings.Split(b.String(), "\n"), strings.Split(renderText(t, r), "\n")
+	if len(ascii) != len(text) {
+		t.Fatalf("RenderTextASCII: want %d lines, got %d", len(text), len(ascii))
+	}
+	for n := range ascii {
+		if strings.Trim(ascii[n], "+-| EX") != "" {
+			t.Errorf("RenderTextASCII: line %d: want only ASCII, got %q", n, ascii[n])
+		}
+		// every wall and corner lines up with a glyph from RenderText
+		glyphs := []rune(text[n])
+		if len(ascii[n]) != len(glyphs) {
+			t.Errorf("RenderTextASCII: line %d: want %d characters, got %d", n, len(glyphs), len(ascii[n]))
+			continue
+		}
+		for i, glyph := range glyphs {
+			if (ascii[n][i] == ' ') != (glyph == ' ') {
+				t.Errorf("RenderTextASCII: line %d: column %d: got %q where RenderText has %q", n, i, ascii[n][i], glyph)
+			}
+		}
+	}
+}
+
+func TestRenderPNGScaled(t *testing.T) {
+	r := loopMaze(t)
+	decode := func(render func(w io.Writer) error) image.Image {
+		t.Helper()
+		var b bytes.Buffer
+		if err := render(&b); err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		img, err := png.Decode(&b)
+		if err != nil {
+			t.Fatalf("png: %v", err)
+		}
+		return img
+	}
+
+	// cells are 30 pixels wide and 20 tall, with a margin of half the smaller scale on every side
+	const scaleX, scaleY, margin = 30, 20, 10
+	img := decode(func(w io.Writer) error { return r.RenderPNGScaled(w, scaleX, scaleY) })
+	if want := image.Rect(0, 0, 3*scaleX+2*margin, 3*scaleY+2*margin); img.Bounds() != want {
+		t.Errorf("RenderPNGScaled: bounds: want %v, got %v", want, img.Bounds())
+	}
+	// the middle row of cells has walls on the west, between the last two cells, and on the east
+	centers, _ := wallRuns(img, margin+scaleY+scaleY/2)
+	want := []float64{margin, margin + 2*scaleX, margin + 3*scaleX}
+	if len(centers) != len(want) {
+		t.Fatalf("RenderPNGScaled: middle row: want walls at %v, got %v", want, centers)
+	}
+	for n := range want {
+		if math.Abs(centers[n]-want[n]) > 1 {
+			t.Errorf("RenderPNGScaled: middle row: wall %d: want center %g, got %g", n, want[n], centers[n])
+		}
+	}
+
+	// square cells are the same as RenderPNG
+	var got, plain bytes.Buffer
+	if err := r.RenderPNGScaled(&got, 20, 20); err != nil {
+		t.Fatalf("RenderPNGScaled: %v", err)
+	} else if err = r.RenderPNG(&plain, 20); err != nil {
+		t.Fatalf("RenderPNG: %v", err)
+	}
+	if !bytes.Equal(got.Bytes(), plain.Bytes()) {
+		t.Errorf("RenderPNGScaled: square cells differ from RenderPNG")
+	}
+
+	for _, scale := range [][2]int{{0, 10}, {10, 0}, {-1, -1}} {
+		if err := r.RenderPNGScaled(io.Discard, scale[0], scale[1]); err == nil {
+			t.Errorf("RenderPNGScaled: scale %d x %d: want error, got nil", scale[0], scale[1])
+		}
+	}
+}
+
+func TestRenderTextLabeled(t *testing.T) {
+	var b bytes.Buffer
+	if err := loopMaze(t).RenderTextLabeled(&b); err != nil {
+		t.Fatalf("RenderTextLabeled: %v", err)
+	}
+	want := "   0 1 2\n" +
+		"  ╔ ╦═╦═╗\n" +
+		"0 ║E   X \n" +
+		"  ╠ ╬═╬ ╣\n" +
+		"1 ║   ║ ║\n" +
+		"  ╠ ╬═╬ ╣\n" +
+		"2 ║     ║\n" +
+		"  ╚═╩═╩═╝\n" +
+		"   0 1 2\n" +
+		"\n"
+	if got := b.String(); got != want {
+		t.Errorf("RenderTextLabeled: want\n%s\ngot\n%s", want, got)
+	}
+
+	// with more than ten rows and columns, the labels are two digits and the column numbers
+	// take two lines, but the maze itself is the same as RenderText
+	r, err := RectangleMazeWith(12, 11, WilsonGenerator{}, false, WithSeed(1))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	b.Reset()
+	if err := r.RenderTextLabeled(&b); err != nil {
+		t.Fatalf("RenderTextLabeled: %v", err)
+	}
+	labeled, text := strings.Split(b.String(), "\n"), strings.Split(renderText(t, r), "\n")
+	if len(labeled) != len(text)+4 {
+		t.Fatalf("RenderTextLabeled: want %d lines, got %d", len(text)+4, len(labeled))
+	}
+	header := []string{
+		"                        1",
+		"    0 1 2 3 4 5 6 7 8 9 0",
+	}
+	for n, line := range header {
+		if labeled[n] != line || labeled[len(labeled)-4+n] != line {
+			t.Errorf("RenderTextLabeled: header line %d: want %q, got %q and %q", n, line, labeled[n], labeled[len(labeled)-4+n])
+		}
+	}
+	for n, line := range text[:len(text)-2] {
+		label := "   "
+		if n%2 == 1 {
+			label = fmt.Sprintf("%2d ", n/2)
+		}
+		if got := labeled[n+2]; got != label+line {
+			t.Errorf("RenderTextLabeled: line %d: want %q, got %q", n+2, label+line, got)
+		}
+	}
+
+	// RenderText has no labels
+	if strings.ContainsAny(renderText(t, r), "0123456789") {
+		t.Errorf("RenderText: want no labels, got\n%s", renderText(t, r))
+	}
+}
+
+func TestRenderPNGRegion(t *testing.T) {
+	r := loopMaze(t)
+
+	// the whole maze is the same as RenderPNG
+	var got, want bytes.Buffer
+	if err := r.RenderPNGRegion(&got, 20, 0, 0, 3, 3); err != nil {
+		t.Fatalf("RenderPNGRegion: %v", err)
+	} else if err = r.RenderPNG(&want, 20); err != nil {
+		t.Fatalf("RenderPNG: %v", err)
+	}
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Errorf("RenderPNGRegion: whole maze differs from RenderPNG")
+	}
+
+	// the south-east corner of the maze. the passage into the region from the west stays open,
+	// and the walls on the other edges are drawn.
+	const scale, margin = 20, 10
+	got.Reset()
+	if err := r.RenderPNGRegion(&got, scale, 1, 1, 2, 2); err != nil {
+		t.Fatalf("RenderPNGRegion: %v", err)
+	}
+	img, err := png.Decode(&got)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	if want := image.Rect(0, 0, 2*scale+2*margin, 2*scale+2*margin); img.Bounds() != want {
+		t.Errorf("RenderPNGRegion: bounds: want %v, got %v", want, img.Bounds())
+	}
+	for _, tc := range []struct {
+		name string
+		y    int
+		want []float64
+	}{
+		{"first row", margin + scale/2, []float64{margin + scale, margin + 2*scale}},
+		{"second row", margin + scale + scale/2, []float64{margin + 2*scale}},
+	} {
+		centers, _ := wallRuns(img, tc.y)
+		if len(centers) != len(tc.want) {
+			t.Errorf("RenderPNGRegion: %s: want walls at %v, got %v", tc.name, tc.want, centers)
+			continue
+		}
+		for n := range tc.want {
+			if math.Abs(centers[n]-tc.want[n]) > 1 {
+				t.Errorf("RenderPNGRegion: %s: wall %d: want center %g, got %g", tc.name, n, tc.want[n], centers[n])
+			}
+		}
+	}
+
+	for _, region := range [][4]int{
+		{0, 0, 0, 1},
+		{0, 0, 1, 0},
+		{-1, 0, 2, 2},
+		{0, -1, 2, 2},
+		{2, 2, 2, 1},
+		{0, 0, 3, 4},
+	} {
+		if err := r.RenderPNGRegion(io.Discard, scale, region[0], region[1], region[2], region[3]); err == nil {
+			t.Errorf("RenderPNGRegion: region %v: want error, got nil", region)
+		}
+	}
+}
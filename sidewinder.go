@@ -0,0 +1,36 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// RectangleSidewinder creates a maze using the sidewinder algorithm.
+// the top row is always a single open corridor.
+func RectangleSidewinder(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, SidewinderGenerator{}, solve)
+}
+
+// carveSidewinder carves each row as a series of horizontal runs.
+// when a run ends, one random cell from the run is linked to its northern neighbor.
+func (g *grid) carveSidewinder(rng *rand.Rand) {
+	for row := 0; row < g.height; row++ {
+		var run []*cell
+		for col := 0; col < g.width; col++ {
+			c := g.cells[row][col]
+			c.in = true
+			run = append(run, c)
+
+			// the run must end at the eastern edge and can't end on the top row
+			atEasternEdge, atNorthernEdge := c.neighbors.east == nil, c.neighbors.north == nil
+			if atEasternEdge || (!atNorthernEdge && rng.Intn(2) == 0) {
+				if !atNorthernEdge {
+					member := run[rng.Intn(len(run))]
+					member.linkTo(member.neighbors.north)
+				}
+				run = nil
+			} else {
+				c.linkTo(c.neighbors.east)
+			}
+		}
+	}
+}
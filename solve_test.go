@@ -0,0 +1,291 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// branchMaze returns a 3 x 3 maze with the entrance at the north-west corner and the exit at the south-east corner.
+// depth-first search tries the dead end down the western column and along the southern row
+// before it backs out and finds the way through the center.
+func branchMaze(t *testing.T) *Rectangle {
+	t.Helper()
+	return testMaze(t, 3, 3, [2]int{0, 0}, [2]int{2, 2},
+		passage{{0, 0}, {1, 0}}, passage{{1, 0}, {2, 0}}, passage{{2, 0}, {2, 1}},
+		passage{{1, 0}, {1, 1}}, passage{{1, 1}, {1, 2}}, passage{{1, 2}, {2, 2}},
+		passage{{0, 0}, {0, 1}}, passage{{0, 1}, {0, 2}},
+	)
+}
+
+func TestSolveTrace(t *testing.T) {
+	r := branchMaze(t)
+	if trace := r.SolveTrace(); trace != nil {
+		t.Errorf("SolveTrace: unsolved: want nil, got %v", trace)
+	}
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	// the trace starts at the entrance and includes the dead end that the search backed out of
+	want := [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {2, 2}}
+	if got := r.SolveTrace(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SolveTrace: want %v, got %v", want, got)
+	}
+	if want, got := [][2]int{{0, 0}, {1, 0}, {1, 1}, {1, 2}, {2, 2}}, r.SolutionPath(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SolutionPath: want %v, got %v", want, got)
+	}
+
+	// the other solvers don't record a trace
+	if err := r.SolveBFS(); err != nil {
+		t.Fatalf("SolveBFS: %v", err)
+	}
+	if trace := r.SolveTrace(); trace != nil {
+		t.Errorf("SolveTrace: after SolveBFS: want nil, got %v", trace)
+	}
+}
+
+func TestSolveConcurrent(t *testing.T) {
+	r, err := RectangleMazeWith(20, 20, WilsonGenerator{}, false, WithSeed(5))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	// run with -race to check that the solvers share the maze safely
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			var err error
+			switch n % 4 {
+			case 0:
+				err = r.Solve()
+			case 1:
+				err = r.SolveBFS()
+			case 2:
+				err = r.SolveAStar()
+			case 3:
+				if r.SolutionLength() == 0 {
+					t.Errorf("SolutionLength: want a path, got none")
+				}
+			}
+			if err != nil {
+				t.Errorf("solver %d: %v", n, err)
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	// a perfect maze has only one path, so every solver agrees on it
+	if got, want := r.SolutionLength(), len(r.SolutionPath()); got != want || got == 0 {
+		t.Errorf("SolutionLength: want %d, got %d", want, got)
+	}
+}
+
+func TestSolveVisitsCellsOnce(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		r, err := RectangleMazeWith(12, 12, WilsonGenerator{}, false, WithSeed(seed))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		// loops give the search more than one way into a cell
+		r.g.braid(0.5, rand.New(rand.NewSource(seed)))
+		if err := r.Solve(); err != nil {
+			t.Fatalf("seed %d: Solve: %v", seed, err)
+		}
+		seen := map[[2]int]bool{}
+		for _, rc := range r.SolveTrace() {
+			if seen[rc] {
+				t.Fatalf("seed %d: Solve: cell %v explored twice", seed, rc)
+			}
+			seen[rc] = true
+		}
+		// the path is rebuilt from the walk pointers, so it must be a real path from the entrance to the exit
+		path := r.SolutionPath()
+		checkPath(t, r, path)
+		for _, rc := range path {
+			if !seen[rc] {
+				t.Errorf("seed %d: Solve: path cell %v is not in the trace", seed, rc)
+			}
+		}
+	}
+}
+
+func TestSolveNoPath(t *testing.T) {
+	// the exit is walled off from the rest of the maze
+	r := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 1},
+		passage{{0, 0}, {0, 1}}, passage{{0, 0}, {1, 0}},
+	)
+	if err := r.Solve(); err == nil {
+		t.Fatalf("Solve: want error, got nil")
+	}
+	if path := r.SolutionPath(); path != nil {
+		t.Errorf("SolutionPath: want nil, got %v", path)
+	}
+	// a failed search is not cached, so opening a wall lets the next call succeed
+	r.g.cells[0][1].linkTo(r.g.cells[1][1])
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: after opening the wall: %v", err)
+	}
+	if got := len(r.SolutionPath()); got != 3 {
+		t.Errorf("SolutionPath: want 3 cells, got %d", got)
+	}
+}
+
+func TestResetSolution(t *testing.T) {
+	r := branchMaze(t)
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	r.ResetSolution()
+	if r.SolutionPath() != nil || r.SolveTrace() != nil {
+		t.Errorf("ResetSolution: want no path or trace")
+	}
+	for _, c := range r.g.allCells() {
+		if c.onPath || c.visited || c.to != nil {
+			t.Errorf("ResetSolution: cell (%d, %d): want the solver's flags cleared", c.row, c.col)
+		}
+	}
+
+	// move the exit to the end of the dead end and solve again
+	if err := r.SetExit(2, 1); err != nil {
+		t.Fatalf("SetExit: %v", err)
+	}
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if want, got := [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}}, r.SolutionPath(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SolutionPath: want %v, got %v", want, got)
+	}
+	if r.g.cells[1][1].onPath {
+		t.Errorf("Solve: the old path is still marked")
+	}
+}
+
+func TestSolveConcurrentRender(t *testing.T) {
+	r, err := RectangleMazeWith(16, 16, WilsonGenerator{}, false, WithSeed(9))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	// run with -race to check that the renderers only read the maze once Solve returns
+	texts := make([]string, 8)
+	var wg sync.WaitGroup
+	for n := range texts {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			if err := r.Solve(); err != nil {
+				t.Errorf("Solve %d: %v", n, err)
+				return
+			}
+			var b strings.Builder
+			if err := r.RenderText(&b); err != nil {
+				t.Errorf("RenderText %d: %v", n, err)
+			}
+			texts[n] = b.String()
+			if img := r.RenderImage(8); img.Bounds().Empty() {
+				t.Errorf("RenderImage %d: want an image, got %v", n, img.Bounds())
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	// every goroutine saw the same solved maze
+	for n, text := range texts {
+		if text != texts[0] {
+			t.Errorf("RenderText %d: want\n%s\ngot\n%s", n, texts[0], text)
+		}
+	}
+	if !strings.Contains(texts[0], "*") {
+		t.Errorf("RenderText: want the solution path marked:\n%s", texts[0])
+	}
+}
+
+func TestSearchOrder(t *testing.T) {
+	// the neighbors come back north, east, south, west whatever order the walls were opened in
+	r := loopMaze(t)
+	for _, tc := range []struct {
+		row, col int
+		want     [][2]int
+	}{
+		{1, 0, [][2]int{{0, 0}, {1, 1}, {2, 0}}},
+		{0, 1, [][2]int{{0, 2}, {0, 0}}},
+		{1, 2, [][2]int{{0, 2}, {2, 2}}},
+	} {
+		var got [][2]int
+		for _, n := range r.g.cells[tc.row][tc.col].openNeighbors() {
+			got = append(got, [2]int{n.row, n.col})
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("openNeighbors (%d, %d): want %v, got %v", tc.row, tc.col, tc.want, got)
+		}
+	}
+
+	// an open room has many shortest paths. breadth-first search reaches the cells to the east before the
+	// cells to the south, so it takes the northern row. depth-first search pops the last neighbor it pushed,
+	// so it goes south first and takes the western column.
+	room := testMaze(t, 3, 3, [2]int{0, 0}, [2]int{2, 2},
+		passage{{0, 0}, {0, 1}}, passage{{0, 1}, {0, 2}},
+		passage{{1, 0}, {1, 1}}, passage{{1, 1}, {1, 2}},
+		passage{{2, 0}, {2, 1}}, passage{{2, 1}, {2, 2}},
+		passage{{0, 0}, {1, 0}}, passage{{1, 0}, {2, 0}},
+		passage{{0, 1}, {1, 1}}, passage{{1, 1}, {2, 1}},
+		passage{{0, 2}, {1, 2}}, passage{{1, 2}, {2, 2}},
+	)
+	for _, tc := range []struct {
+		name  string
+		solve func(r *Rectangle) error
+		want  [][2]int
+	}{
+		{"Solve", (*Rectangle).Solve, [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}}},
+		{"SolveBFS", (*Rectangle).SolveBFS, [][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 2}, {2, 2}}},
+	} {
+		for run := 0; run < 3; run++ {
+			room.ResetSolution()
+			if err := tc.solve(room); err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			if got := room.SolutionPath(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("%s: run %d: want %v, got %v", tc.name, run, tc.want, got)
+			}
+		}
+	}
+}
+
+func TestSolveRepeatable(t *testing.T) {
+	// the braided mazes have loops, so the solvers have choices to make
+	build := func() *Rectangle {
+		t.Helper()
+		r, err := RectangleMazeWith(15, 15, WilsonGenerator{}, false, WithSeed(12))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		r.g.braid(1, rand.New(rand.NewSource(12)))
+		return r
+	}
+	for _, tc := range []struct {
+		name  string
+		solve func(r *Rectangle) error
+	}{
+		{"Solve", (*Rectangle).Solve},
+		{"SolveBFS", (*Rectangle).SolveBFS},
+		{"SolveAStar", (*Rectangle).SolveAStar},
+		{"SolveDijkstra", (*Rectangle).SolveDijkstra},
+	} {
+		var first [][2]int
+		for run := 0; run < 5; run++ {
+			r := build()
+			if err := tc.solve(r); err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			if run == 0 {
+				first = r.SolutionPath()
+			} else if got := r.SolutionPath(); !reflect.DeepEqual(got, first) {
+				t.Errorf("%s: run %d: same seed gave a different path:\nwant %v\ngot  %v", tc.name, run, first, got)
+			}
+		}
+	}
+}
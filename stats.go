@@ -0,0 +1,167 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+// MazeStats holds metrics describing the structure of a maze.
+type MazeStats struct {
+	// DeadEnds is the number of cells with exactly one open passage, not counting the entrances
+	// and exits, like the DeadEnds method.
+	DeadEnds int
+	// Junctions is the number of cells with three or more open passages.
+	Junctions int
+	// Corridors is the number of cells with two open passages on opposite sides.
+	Corridors int
+	// SolutionLength is the number of cells on the solution path.
+	// it is zero if the maze hasn't been solved.
+	SolutionLength int
+}
+
+// Stats inspects the open passages of every cell and returns metrics for the maze.
+func (r *Rectangle) Stats() MazeStats {
+	var stats MazeStats
+	for _, c := range r.g.allCells() {
+		switch len(c.openNeighbors()) {
+		case 1:
+			if !c.isEntrance() && !c.isExit() {
+				stats.DeadEnds++
+			}
+		case 2:
+			if (c.northIsOpen() && c.southIsOpen()) || (c.eastIsOpen() && c.westIsOpen()) {
+				stats.Corridors++
+			}
+		case 3, 4:
+			stats.Junctions++
+		}
+		if c.onPath {
+			stats.SolutionLength++
+		}
+	}
+	return stats
+}
+
+// DeadEnds returns the coordinates of every cell with exactly one open passage, in row-major order.
+// the entrances and exits are not included, since their outer walls are open as well.
+func (r *Rectangle) DeadEnds() [][2]int {
+	var deadEnds [][2]int
+	for _, c := range r.g.allCells() {
+		if c.isDeadEnd() && !c.isEntrance() && !c.isExit() {
+			deadEnds = append(deadEnds, [2]int{c.row, c.col})
+		}
+	}
+	return deadEnds
+}
+
+// IsPerfect returns true if every cell can be reached from the entrance and there are no loops.
+// a maze with n cells is perfect when it is connected and has exactly n-1 open passages.
+// masked cells are not part of the maze and aren't counted.
+func (r *Rectangle) IsPerfect() bool {
+	cells, passages := 0, 0
+	for _, c := range r.g.allCells() {
+		if c.masked {
+			continue
+		}
+		cells++
+		// every passage is seen from both ends, so only count it from the first cell
+		for _, neighbor := range c.openNeighbors() {
+			if neighbor.row > c.row || (neighbor.row == c.row && neighbor.col > c.col) {
+				passages++
+			}
+		}
+	}
+	if passages != cells-1 {
+		return false
+	}
+
+	reached := 0
+	for _, row := range r.g.distancesFrom(r.entrances[0]) {
+		for _, distance := range row {
+			if distance != -1 {
+				reached++
+			}
+		}
+	}
+	return reached == cells
+}
+
+// MaxSolutions is the most solutions that CountSolutions will count before it gives up.
+// the number of paths through a maze with many loops grows very quickly.
+const MaxSolutions = 1000
+
+// maxSolutionVisits is the most cells that CountSolutions will visit before it gives up.
+// the search tries every path that doesn't visit a cell twice, and a large open area has
+// far too many of them even if none of them reach an exit.
+const maxSolutionVisits = 1 << 20
+
+// CountSolutions returns the number of distinct paths from an entrance to an exit that don't visit any cell twice.
+// a perfect maze has exactly one. it stops counting at MaxSolutions, and it returns MaxSolutions if
+// the maze is too open to finish the search, even if fewer paths have been found.
+func (r *Rectangle) CountSolutions() int {
+	count, visits := 0, 0
+	onPath := make(map[*cell]bool)
+	var walk func(c *cell)
+	walk = func(c *cell) {
+		if visits++; visits > maxSolutionVisits {
+			count = MaxSolutions
+		}
+		if count >= MaxSolutions {
+			return
+		} else if c.isExit() {
+			count++
+			return
+		}
+		onPath[c] = true
+		for _, neighbor := range c.openNeighbors() {
+			if !onPath[neighbor] {
+				walk(neighbor)
+			}
+		}
+		onPath[c] = false
+	}
+	for _, entrance := range r.entrances {
+		walk(entrance)
+	}
+	return count
+}
+
+// Difficulty returns an estimate of how hard the maze is to solve, from 0 for a maze with
+// no wrong turns up to 1. it returns 0 if the maze hasn't been solved.
+//
+// the score is
+//
+//	branching * (1 + length + deadEnds) / 3
+//
+// where branching is the number of false branches leaving the solution path divided by the
+// number of cells on the path (capped at 1), length is the fraction of the cells that are on
+// the path, and deadEnds is the fraction of the cells that are dead ends. a path with no false
+// branches scores 0 no matter how long it is, since there is never a wrong turn to take.
+func (r *Rectangle) Difficulty() float64 {
+	if !r.solved {
+		return 0
+	}
+	cells, pathCells, branches, deadEnds := 0, 0, 0, 0
+	for _, c := range r.g.allCells() {
+		if c.masked {
+			continue
+		}
+		cells++
+		if c.isDeadEnd() && !c.isEntrance() && !c.isExit() {
+			deadEnds++
+		}
+		if !c.onPath {
+			continue
+		}
+		pathCells++
+		for _, neighbor := range c.openNeighbors() {
+			if !neighbor.onPath {
+				branches++
+			}
+		}
+	}
+	if pathCells == 0 {
+		return 0
+	}
+	branching := min(float64(branches)/float64(pathCells), 1)
+	length := float64(pathCells) / float64(cells)
+	deadEndRatio := float64(deadEnds) / float64(cells)
+	return branching * (1 + length + deadEndRatio) / 3
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIsPerfect(t *testing.T) {
+	r, err := RectangleMazeWith(8, 11, WilsonGenerator{}, false, WithSeed(6))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	if !r.IsPerfect() {
+		t.Fatalf("IsPerfect: generated maze: want true, got false")
+	}
+
+	// opening any closed wall between two cells in a perfect maze adds a loop
+	var c *cell
+	for _, c = range r.g.allCells() {
+		if c.neighbors.east != nil && c.walls.east {
+			break
+		}
+	}
+	c.linkTo(c.neighbors.east)
+	if r.IsPerfect() {
+		t.Errorf("IsPerfect: added a loop at (%d, %d): want false, got true", c.row, c.col)
+	}
+
+	if loopMaze(t).IsPerfect() {
+		t.Errorf("IsPerfect: loop maze: want false, got true")
+	}
+
+	// the exit is walled off from the rest of the maze
+	sealed := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 1},
+		passage{{0, 0}, {0, 1}}, passage{{0, 0}, {1, 0}},
+	)
+	if sealed.IsPerfect() {
+		t.Errorf("IsPerfect: unreachable cell: want false, got true")
+	}
+}
+
+func TestCountSolutions(t *testing.T) {
+	r, err := RectangleMazeWith(8, 8, WilsonGenerator{}, false, WithSeed(2))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	if got := r.CountSolutions(); got != 1 {
+		t.Errorf("CountSolutions: perfect maze: want 1, got %d", got)
+	}
+
+	// one loop gives two ways around it
+	if got := loopMaze(t).CountSolutions(); got != 2 {
+		t.Errorf("CountSolutions: one loop: want 2, got %d", got)
+	}
+
+	sealed := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 1},
+		passage{{0, 0}, {0, 1}}, passage{{0, 0}, {1, 0}},
+	)
+	if got := sealed.CountSolutions(); got != 0 {
+		t.Errorf("CountSolutions: walled off: want 0, got %d", got)
+	}
+
+	// with every inner wall removed there are far too many paths to count
+	open := RectangleFromGrid(NewGrid(6, 6), false)
+	for _, c := range open.g.allCells() {
+		for _, neighbor := range c.neighborhood {
+			c.linkTo(neighbor)
+		}
+	}
+	if got := open.CountSolutions(); got != MaxSolutions {
+		t.Errorf("CountSolutions: open grid: want %d, got %d", MaxSolutions, got)
+	}
+}
+
+func TestCountSolutionsSealedRoom(t *testing.T) {
+	// the entrance opens into a room with no inner walls, but the room is walled off from the exit,
+	// so there are no solutions to count but more paths through the room than could ever be searched
+	r, err := RectangleMazeWith(10, 10, WilsonGenerator{}, false, WithSeed(5), WithGatePlacement(OppositeCorners))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	if err := r.CarveRoom(0, 0, 8, 8); err != nil {
+		t.Fatalf("CarveRoom: %v", err)
+	}
+	for n := 0; n < 8; n++ {
+		r.g.cells[n][7].unlinkFrom(r.g.cells[n][8])
+		r.g.cells[7][n].unlinkFrom(r.g.cells[8][n])
+	}
+	if err := r.SolveBFS(); err == nil {
+		t.Fatalf("SolveBFS: want the exit walled off, got a path")
+	}
+
+	done := make(chan int)
+	go func() { done <- r.CountSolutions() }()
+	select {
+	case got := <-done:
+		if got != MaxSolutions {
+			t.Errorf("CountSolutions: sealed room: want %d, got %d", MaxSolutions, got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("CountSolutions: sealed room: still searching after 10 seconds")
+	}
+}
+
+func TestDeadEnds(t *testing.T) {
+	// the exit at the south-east corner is a dead end too, but it isn't counted
+	want := [][2]int{{0, 2}, {2, 1}}
+	if got := branchMaze(t).DeadEnds(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeadEnds: want %v, got %v", want, got)
+	}
+
+	// a single corridor has dead ends only at the gates
+	corridor := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 0},
+		passage{{0, 0}, {0, 1}}, passage{{0, 1}, {1, 1}}, passage{{1, 1}, {1, 0}},
+	)
+	if got := corridor.DeadEnds(); got != nil {
+		t.Errorf("DeadEnds: corridor: want none, got %v", got)
+	}
+}
+
+func TestStats(t *testing.T) {
+	r := branchMaze(t)
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	// the exit is a dead end too, but the gates aren't counted
+	want := MazeStats{DeadEnds: 2, Junctions: 1, Corridors: 2, SolutionLength: 5}
+	if got := r.Stats(); got != want {
+		t.Errorf("Stats: want %+v, got %+v", want, got)
+	}
+
+	// Stats and DeadEnds count the same cells
+	for seed := int64(1); seed <= 5; seed++ {
+		r, err := RectangleMazeWith(9, 9, WilsonGenerator{}, false, WithSeed(seed))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		if stats, deadEnds := r.Stats(), r.DeadEnds(); stats.DeadEnds != len(deadEnds) {
+			t.Errorf("seed %d: Stats: %d dead ends, but DeadEnds found %d", seed, stats.DeadEnds, len(deadEnds))
+		}
+	}
+}
+
+func TestDifficulty(t *testing.T) {
+	corridor := testMaze(t, 2, 2, [2]int{0, 0}, [2]int{1, 0},
+		passage{{0, 0}, {0, 1}}, passage{{0, 1}, {1, 1}}, passage{{1, 1}, {1, 0}},
+	)
+	if got := corridor.Difficulty(); got != 0 {
+		t.Errorf("Difficulty: unsolved: want 0, got %g", got)
+	}
+	if err := corridor.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	// a corridor has no wrong turns
+	if got := corridor.Difficulty(); got != 0 {
+		t.Errorf("Difficulty: corridor: want 0, got %g", got)
+	}
+
+	// the path has five cells with two false branches, and there are two dead ends in nine cells
+	r := branchMaze(t)
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	want := 2.0 / 5 * (1 + 5.0/9 + 2.0/9) / 3
+	if got := r.Difficulty(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Difficulty: branches: want %g, got %g", want, got)
+	}
+
+	for seed := int64(1); seed <= 5; seed++ {
+		r, err := RectangleMazeWith(20, 20, WilsonGenerator{}, true, WithSeed(seed))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		if got := r.Difficulty(); got <= 0 || got > 1 {
+			t.Errorf("Difficulty: seed %d: want a score in (0, 1], got %g", seed, got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// SymmetryAxis is the symmetry of a maze created by RectangleSymmetric.
+type SymmetryAxis int
+
+const (
+	// Vertical mirrors the western half of the maze onto the eastern half.
+	Vertical SymmetryAxis = iota
+	// Horizontal mirrors the northern half of the maze onto the southern half.
+	Horizontal
+	// Rotational rotates the northern half of the maze by 180 degrees onto the southern half.
+	Rotational
+)
+
+// String implements the Stringer interface.
+func (a SymmetryAxis) String() string {
+	switch a {
+	case Vertical:
+		return "vertical"
+	case Horizontal:
+		return "horizontal"
+	case Rotational:
+		return "rotational"
+	}
+	return fmt.Sprintf("SymmetryAxis(%d)", int(a))
+}
+
+// RectangleSymmetric creates a maze whose walls are symmetric about the axis.
+// one half of the maze is carved with Wilson's algorithm and copied onto the other half.
+// the walls are symmetric, but the entrance and exit are placed like RectangleMaze does, so they usually aren't.
+//
+// the maze is not always perfect. when the axis runs through the middle of a row or column of cells,
+// the two halves share those cells and the copies of the paths through them can form small loops.
+// otherwise the halves are joined by opening the wall across the axis in one place, which for
+// Rotational symmetry also means opening its rotated copy, and that can form one loop.
+func RectangleSymmetric(height, width int, axis SymmetryAxis, solve bool) (*Rectangle, error) {
+	switch axis {
+	case Vertical, Horizontal, Rotational:
+	default:
+		return nil, fmt.Errorf("symmetric: invalid axis %d", axis)
+	}
+	return RectangleMazeWith(height, width, SymmetricGenerator{Axis: axis}, solve)
+}
+
+// SymmetricGenerator carves mazes whose walls are symmetric about an axis, like RectangleSymmetric.
+type SymmetricGenerator struct {
+	Axis SymmetryAxis
+}
+
+func (gen SymmetricGenerator) Carve(g *Grid, rng *rand.Rand) { g.g.carveSymmetric(gen.Axis, rng) }
+
+// carveSymmetric carves one half of the grid with Wilson's algorithm and copies every passage
+// to the matching cells in the other half. an invalid axis is treated as Vertical.
+func (g *grid) carveSymmetric(axis SymmetryAxis, rng *rand.Rand) {
+	if axis != Horizontal && axis != Rotational {
+		axis = Vertical
+	}
+
+	// the half includes the middle row or column when the axis runs through it.
+	// mirror returns the cell that matches the cell at row, col on the other side of the axis.
+	halfHeight, halfWidth := g.height, (g.width+1)/2
+	mirror := func(row, col int) *cell {
+		return g.cells[row][g.width-1-col]
+	}
+	switch axis {
+	case Horizontal:
+		halfHeight, halfWidth = (g.height+1)/2, g.width
+		mirror = func(row, col int) *cell {
+			return g.cells[g.height-1-row][col]
+		}
+	case Rotational:
+		halfHeight, halfWidth = (g.height+1)/2, g.width
+		mirror = func(row, col int) *cell {
+			return g.cells[g.height-1-row][g.width-1-col]
+		}
+	}
+
+	// carve the half on its own grid so that the walks can't leave it
+	half := createGrid(halfHeight, halfWidth)
+	half.carveWilson(rng)
+
+	// copy the passages to both sides of the axis. checking only east and south visits each passage once.
+	for _, c := range half.allCells() {
+		for _, neighbor := range []*cell{c.neighbors.east, c.neighbors.south} {
+			if neighbor != nil && c.isOpenTo(neighbor) {
+				g.cells[c.row][c.col].linkTo(g.cells[neighbor.row][neighbor.col])
+				mirror(c.row, c.col).linkTo(mirror(neighbor.row, neighbor.col))
+			}
+		}
+	}
+
+	// when the axis runs between two rows or columns, the halves aren't connected yet,
+	// so open the wall across the axis in one place, along with its copy
+	if axis == Vertical && g.width%2 == 0 {
+		c := g.cells[rng.Intn(g.height)][g.width/2-1]
+		c.linkTo(c.neighbors.east)
+	} else if axis != Vertical && g.height%2 == 0 {
+		c := g.cells[g.height/2-1][rng.Intn(g.width)]
+		c.linkTo(c.neighbors.south)
+		mirror(c.row, c.col).linkTo(mirror(c.neighbors.south.row, c.neighbors.south.col))
+	}
+
+	for _, c := range g.allCells() {
+		c.in = true
+	}
+}
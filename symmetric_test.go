@@ -0,0 +1,63 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+func TestRectangleSymmetric(t *testing.T) {
+	for _, tc := range []struct {
+		axis SymmetryAxis
+		// mirror returns the cell and direction that match the cell and direction on the other side of the axis
+		mirror func(height, width, row, col int, dir Direction) (int, int, Direction)
+	}{
+		{Vertical, func(height, width, row, col int, dir Direction) (int, int, Direction) {
+			if dir == East || dir == West {
+				dir = dir.opposite()
+			}
+			return row, width - 1 - col, dir
+		}},
+		{Horizontal, func(height, width, row, col int, dir Direction) (int, int, Direction) {
+			if dir == North || dir == South {
+				dir = dir.opposite()
+			}
+			return height - 1 - row, col, dir
+		}},
+		{Rotational, func(height, width, row, col int, dir Direction) (int, int, Direction) {
+			return height - 1 - row, width - 1 - col, dir.opposite()
+		}},
+	} {
+		// odd sizes put the axis through a row or column of cells, even sizes put it between two
+		for _, size := range [][2]int{{6, 8}, {7, 9}, {6, 9}, {7, 8}} {
+			height, width := size[0], size[1]
+			r, err := RectangleSymmetric(height, width, tc.axis, false)
+			if err != nil {
+				t.Fatalf("%v: RectangleSymmetric: %v", tc.axis, err)
+			}
+			// compare the inner walls only, since the gates aren't placed symmetrically
+			for _, c := range r.g.allCells() {
+				for _, dir := range []Direction{North, East, South, West} {
+					if c.neighbor(dir) == nil {
+						continue
+					}
+					row, col, mdir := tc.mirror(height, width, c.row, c.col, dir)
+					if m := r.g.cells[row][col]; c.wall(dir) != m.wall(mdir) {
+						t.Errorf("%v: %d x %d: (%d, %d) %v is %v but (%d, %d) %v is %v",
+							tc.axis, height, width, c.row, c.col, dir, c.wall(dir), row, col, mdir, m.wall(mdir))
+					}
+				}
+			}
+			// the halves are joined, so every cell can be reached from the entrance
+			for row, steps := range r.DistanceField() {
+				for col, n := range steps {
+					if n < 0 {
+						t.Errorf("%v: %d x %d: (%d, %d) can't be reached", tc.axis, height, width, row, col)
+					}
+				}
+			}
+		}
+	}
+
+	if _, err := RectangleSymmetric(6, 6, SymmetryAxis(7), false); err == nil {
+		t.Errorf("RectangleSymmetric: invalid axis: want error, got nil")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+// TextStyle holds the glyphs used to render a maze as text.
+// the corners are named for the side of the maze they sit on; the tees are named for the
+// direction of the wall that branches off the straight line. any glyph that is left as zero
+// uses the glyph from doubleLine.
+type TextStyle struct {
+	Horizontal rune // ═
+	Vertical   rune // ║
+	NorthWest  rune // ╔
+	NorthEast  rune // ╗
+	SouthWest  rune // ╚
+	SouthEast  rune // ╝
+	TeeSouth   rune // ╦
+	TeeNorth   rune // ╩
+	TeeEast    rune // ╠
+	TeeWest    rune // ╣
+	Cross      rune // ╬
+	// Space is used for open passages and for the centers of cells that aren't marked.
+	Space rune
+}
+
+// doubleLine holds the glyphs that toRunes draws with. it is the only copy of them,
+// so the other styles are mapped from it.
+var doubleLine = TextStyle{
+	Horizontal: '═', Vertical: '║',
+	NorthWest: '╔', NorthEast: '╗', SouthWest: '╚', SouthEast: '╝',
+	TeeSouth: '╦', TeeNorth: '╩', TeeEast: '╠', TeeWest: '╣',
+	Cross: '╬', Space: ' ',
+}
+
+// DoubleLine returns the style used by RenderText.
+func DoubleLine() TextStyle {
+	return doubleLine
+}
+
+// SingleLine returns a style that uses the light box drawing glyphs.
+func SingleLine() TextStyle {
+	return TextStyle{
+		Horizontal: '─', Vertical: '│',
+		NorthWest: '┌', NorthEast: '┐', SouthWest: '└', SouthEast: '┘',
+		TeeSouth: '┬', TeeNorth: '┴', TeeEast: '├', TeeWest: '┤',
+		Cross: '┼', Space: ' ',
+	}
+}
+
+// Rounded returns a style like SingleLine with rounded corners.
+func Rounded() TextStyle {
+	style := SingleLine()
+	style.NorthWest, style.NorthEast, style.SouthWest, style.SouthEast = '╭', '╮', '╰', '╯'
+	return style
+}
+
+// ASCII returns the style used by RenderTextASCII.
+func ASCII() TextStyle {
+	return TextStyle{
+		Horizontal: '-', Vertical: '|',
+		NorthWest: '+', NorthEast: '+', SouthWest: '+', SouthEast: '+',
+		TeeSouth: '+', TeeNorth: '+', TeeEast: '+', TeeWest: '+',
+		Cross: '+', Space: ' ',
+	}
+}
+
+// glyphs maps each glyph drawn by toRunes to the glyph from the style, leaving out the ones that aren't set.
+func (style TextStyle) glyphs() map[rune]rune {
+	glyphs := make(map[rune]rune)
+	for _, pair := range [][2]rune{
+		{doubleLine.Horizontal, style.Horizontal},
+		{doubleLine.Vertical, style.Vertical},
+		{doubleLine.NorthWest, style.NorthWest},
+		{doubleLine.NorthEast, style.NorthEast},
+		{doubleLine.SouthWest, style.SouthWest},
+		{doubleLine.SouthEast, style.SouthEast},
+		{doubleLine.TeeSouth, style.TeeSouth},
+		{doubleLine.TeeNorth, style.TeeNorth},
+		{doubleLine.TeeEast, style.TeeEast},
+		{doubleLine.TeeWest, style.TeeWest},
+		{doubleLine.Cross, style.Cross},
+		{doubleLine.Space, style.Space},
+	} {
+		if pair[1] != 0 {
+			glyphs[pair[0]] = pair[1]
+		}
+	}
+	return glyphs
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// renderStyled returns the maze rendered as text with the style, split into lines.
+func renderStyled(t *testing.T, r *Rectangle, style TextStyle) []string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := r.RenderTextStyled(&b, style); err != nil {
+		t.Fatalf("RenderTextStyled: %v", err)
+	}
+	return strings.Split(b.String(), "\n")
+}
+
+func TestRenderTextStyled(t *testing.T) {
+	r := loopMaze(t)
+
+	lines := renderStyled(t, r, SingleLine())
+	if got := []rune(lines[0])[0]; got != '┌' {
+		t.Errorf("SingleLine: northwest corner: want '┌', got %q", got)
+	}
+	if got := []rune(lines[len(lines)-3])[0]; got != '└' {
+		t.Errorf("SingleLine: southwest corner: want '└', got %q", got)
+	}
+	if strings.ContainsAny(strings.Join(lines, ""), "═║╔╗╚╝╦╩╠╣╬") {
+		t.Errorf("SingleLine: output contains double line glyphs")
+	}
+
+	if got := []rune(renderStyled(t, r, Rounded())[0])[0]; got != '╭' {
+		t.Errorf("Rounded: northwest corner: want '╭', got %q", got)
+	}
+	for _, line := range renderStyled(t, r, ASCII()) {
+		for _, ch := range line {
+			if ch > 0x7f {
+				t.Fatalf("ASCII: output contains %q", ch)
+			}
+		}
+	}
+
+	// the zero style and DoubleLine both match RenderText
+	want := strings.Split(renderText(t, r), "\n")
+	for _, style := range []TextStyle{{}, DoubleLine()} {
+		if got := renderStyled(t, r, style); strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("%+v: want\n%s\ngot\n%s", style, strings.Join(want, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
+
+func TestTextStylePresetsCannotBeChanged(t *testing.T) {
+	style := DoubleLine()
+	style.Horizontal = '~'
+	if DoubleLine().Horizontal != '═' {
+		t.Errorf("DoubleLine: changing a copy changed the preset")
+	}
+	if got := renderText(t, loopMaze(t)); strings.ContainsRune(got, '~') {
+		t.Errorf("RenderText: changing a copy of the preset changed the output")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// TileMazes stitches a grid of mazes into one large maze. tiles[0][0] is the northwest corner.
+// every tile must have the same dimensions and every row must have the same number of tiles.
+// the walls between tiles are closed and then a single doorway is opened at random between each pair
+// of adjacent tiles, so the whole maze is connected. when the tiles form a block of 2 x 2 or more,
+// the doorways make loops, so the result is not a perfect maze.
+// the gates of the tiles are dropped and a new entrance and exit are placed like RectangleMaze does.
+func TileMazes(tiles [][]*Rectangle) (*Rectangle, error) {
+	if len(tiles) == 0 || len(tiles[0]) == 0 {
+		return nil, fmt.Errorf("tile: no tiles")
+	}
+	tileHeight, tileWidth := tiles[0][0].g.height, tiles[0][0].g.width
+	for row := range tiles {
+		if len(tiles[row]) != len(tiles[0]) {
+			return nil, fmt.Errorf("tile: row %d: want %d tiles, got %d", row, len(tiles[0]), len(tiles[row]))
+		}
+		for col, tile := range tiles[row] {
+			if tile == nil {
+				return nil, fmt.Errorf("tile: tile (%d, %d) is nil", row, col)
+			} else if tile.g.height != tileHeight || tile.g.width != tileWidth {
+				return nil, fmt.Errorf("tile: tile (%d, %d): want %d x %d cells, got %d x %d", row, col, tileHeight, tileWidth, tile.g.height, tile.g.width)
+			}
+		}
+	}
+	height, width := len(tiles)*tileHeight, len(tiles[0])*tileWidth
+	return RectangleMazeWith(height, width, tileGenerator{tiles: tiles}, false)
+}
+
+// tileGenerator carves a grid by copying the walls of a block of equal-sized tiles into it
+// and opening a doorway between each pair of adjacent tiles.
+type tileGenerator struct {
+	tiles [][]*Rectangle
+}
+
+func (gen tileGenerator) Carve(grid *Grid, rng *rand.Rand) {
+	g, tiles := grid.g, gen.tiles
+	tileHeight, tileWidth := tiles[0][0].g.height, tiles[0][0].g.width
+
+	// copy the walls of every tile into the large grid
+	for row, c := range g.cells {
+		for col := range c {
+			from := tiles[row/tileHeight][col/tileWidth].g.cells[row%tileHeight][col%tileWidth]
+			to := g.cells[row][col]
+			to.walls, to.under = from.walls, from.under
+			to.in = true
+		}
+	}
+
+	// close the walls between tiles, which are still open wherever a tile had a gate
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			c := g.cells[row][col]
+			if c.neighbors.east != nil && (col+1)%tileWidth == 0 {
+				c.walls.east, c.neighbors.east.walls.west = true, true
+			}
+			if c.neighbors.south != nil && (row+1)%tileHeight == 0 {
+				c.walls.south, c.neighbors.south.walls.north = true, true
+			}
+		}
+	}
+	// and seal the outer edges of the large maze
+	for _, c := range g.allCells() {
+		g.closeGate(c)
+	}
+
+	// open a doorway at a random spot on the shared edge of each pair of adjacent tiles
+	for row := range tiles {
+		for col := range tiles[row] {
+			if col+1 < len(tiles[row]) {
+				c := g.cells[row*tileHeight+rng.Intn(tileHeight)][(col+1)*tileWidth-1]
+				c.linkTo(c.neighbors.east)
+			}
+			if row+1 < len(tiles) {
+				c := g.cells[(row+1)*tileHeight-1][col*tileWidth+rng.Intn(tileWidth)]
+				c.linkTo(c.neighbors.south)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+// tileMaze returns a seeded 4 x 5 maze to use as a tile.
+func tileMaze(t *testing.T, seed int64) *Rectangle {
+	t.Helper()
+	r, err := RectangleMazeWith(4, 5, WilsonGenerator{}, false, WithSeed(seed))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	return r
+}
+
+func TestTileMazes(t *testing.T) {
+	tiles := [][]*Rectangle{
+		{tileMaze(t, 1), tileMaze(t, 2), tileMaze(t, 3)},
+		{tileMaze(t, 4), tileMaze(t, 5), tileMaze(t, 6)},
+	}
+	before := renderText(t, tiles[0][0])
+	r, err := TileMazes(tiles)
+	if err != nil {
+		t.Fatalf("TileMazes: %v", err)
+	}
+	if r.g.height != 8 || r.g.width != 15 {
+		t.Fatalf("TileMazes: want 8 x 15, got %d x %d", r.g.height, r.g.width)
+	}
+	if renderText(t, tiles[0][0]) != before {
+		t.Errorf("TileMazes: the tiles were changed")
+	}
+
+	// every cell can be reached from the entrance
+	for row, distances := range r.DistanceField() {
+		for col, distance := range distances {
+			if distance == -1 {
+				t.Errorf("TileMazes: cell (%d, %d) can't be reached", row, col)
+			}
+		}
+	}
+	if err := r.Solve(); err != nil {
+		t.Errorf("Solve: %v", err)
+	}
+
+	// there is exactly one doorway between each pair of adjacent tiles
+	doorways := func(cells []*cell, dir Direction) (n int) {
+		for _, c := range cells {
+			if !c.wall(dir) {
+				n++
+			}
+		}
+		return n
+	}
+	for tileRow := 0; tileRow < 2; tileRow++ {
+		for tileCol := 0; tileCol < 2; tileCol++ {
+			var edge []*cell
+			for row := tileRow * 4; row < tileRow*4+4; row++ {
+				edge = append(edge, r.g.cells[row][tileCol*5+4])
+			}
+			if n := doorways(edge, East); n != 1 {
+				t.Errorf("TileMazes: tile (%d, %d): want 1 doorway to the east, got %d", tileRow, tileCol, n)
+			}
+		}
+	}
+	for tileCol := 0; tileCol < 3; tileCol++ {
+		if n := doorways(r.g.cells[3][tileCol*5:tileCol*5+5], South); n != 1 {
+			t.Errorf("TileMazes: tile (0, %d): want 1 doorway to the south, got %d", tileCol, n)
+		}
+	}
+
+	// a single row of tiles has no loops, so it stays perfect
+	row, err := TileMazes([][]*Rectangle{{tileMaze(t, 1), tileMaze(t, 2), tileMaze(t, 3)}})
+	if err != nil {
+		t.Fatalf("TileMazes: %v", err)
+	}
+	if !row.IsPerfect() {
+		t.Errorf("TileMazes: one row: want a perfect maze")
+	}
+}
+
+func TestTileMazesErrors(t *testing.T) {
+	other, err := RectangleMazeWith(4, 6, WilsonGenerator{}, false, WithSeed(1))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	for _, tc := range []struct {
+		name  string
+		tiles [][]*Rectangle
+	}{
+		{"no tiles", nil},
+		{"empty row", [][]*Rectangle{{}}},
+		{"ragged", [][]*Rectangle{{tileMaze(t, 1), tileMaze(t, 2)}, {tileMaze(t, 3)}}},
+		{"nil tile", [][]*Rectangle{{tileMaze(t, 1), nil}}},
+		{"dimensions", [][]*Rectangle{{tileMaze(t, 1), other}}},
+	} {
+		if _, err := TileMazes(tc.tiles); err == nil {
+			t.Errorf("TileMazes: %s: want error, got nil", tc.name)
+		}
+	}
+}
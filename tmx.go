@@ -0,0 +1,52 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// RenderTMX writes the maze as a Tiled map with a single tile layer.
+// the layer uses the same layout as ToGrid, so it has 2*height+1 rows and 2*width+1 columns,
+// with tileWall for walls and corners and tileFloor for cells and open passages.
+// the tile ids are global tile ids and the map doesn't include a tileset, so add one in Tiled
+// (or in your engine) that has those ids. tiles are 16 x 16 pixels.
+// it returns an error for weave mazes, since a single layer can't show a tunnel beneath a crossing.
+func (r *Rectangle) RenderTMX(w io.Writer, tileWall, tileFloor int) error {
+	if r.g.hasCrossings() {
+		return fmt.Errorf("tmx: weave crossings can't be rendered as tiles")
+	}
+	return r.g.toTMX(w, tileWall, tileFloor)
+}
+
+// toTMX writes the grid as a Tiled map, encoding the layer data as CSV.
+func (g *grid) toTMX(w io.Writer, tileWall, tileFloor int) error {
+	tiles := g.toIntGrid()
+	height, width := len(tiles), len(tiles[0])
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(bw, "<map version=\"1.10\" orientation=\"orthogonal\" renderorder=\"right-down\" width=\"%d\" height=\"%d\" tilewidth=\"16\" tileheight=\"16\" infinite=\"0\" nextlayerid=\"2\" nextobjectid=\"1\">\n", width, height)
+	fmt.Fprintf(bw, " <layer id=\"1\" name=\"maze\" width=\"%d\" height=\"%d\">\n", width, height)
+	fmt.Fprintf(bw, "  <data encoding=\"csv\">\n")
+	for row := range tiles {
+		for col, tile := range tiles[row] {
+			id := tileFloor
+			if tile == 1 {
+				id = tileWall
+			}
+			fmt.Fprintf(bw, "%d", id)
+			// every value is followed by a comma except the very last one
+			if row < height-1 || col < width-1 {
+				bw.WriteByte(',')
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	fmt.Fprintf(bw, "</data>\n")
+	fmt.Fprintf(bw, " </layer>\n")
+	fmt.Fprintf(bw, "</map>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRenderTMX(t *testing.T) {
+	r, err := RectangleMazeWith(4, 6, WilsonGenerator{}, false, WithSeed(3))
+	if err != nil {
+		t.Fatalf("RectangleMazeWith: %v", err)
+	}
+	var b bytes.Buffer
+	if err := r.RenderTMX(&b, 7, 2); err != nil {
+		t.Fatalf("RenderTMX: %v", err)
+	}
+	var tmx struct {
+		Width  int `xml:"width,attr"`
+		Height int `xml:"height,attr"`
+		Layer  struct {
+			Width  int `xml:"width,attr"`
+			Height int `xml:"height,attr"`
+			Data   struct {
+				Encoding string `xml:"encoding,attr"`
+				CSV      string `xml:",chardata"`
+			} `xml:"data"`
+		} `xml:"layer"`
+	}
+	if err := xml.Unmarshal(b.Bytes(), &tmx); err != nil {
+		t.Fatalf("RenderTMX: %v", err)
+	}
+	// the layer is the doubled grid, like ToGrid
+	if tmx.Width != 13 || tmx.Height != 9 || tmx.Layer.Width != 13 || tmx.Layer.Height != 9 {
+		t.Fatalf("RenderTMX: want a 13 x 9 map and layer, got %d x %d and %d x %d",
+			tmx.Width, tmx.Height, tmx.Layer.Width, tmx.Layer.Height)
+	}
+	if tmx.Layer.Data.Encoding != "csv" {
+		t.Fatalf("RenderTMX: want csv data, got %q", tmx.Layer.Data.Encoding)
+	}
+
+	values := strings.Split(strings.TrimSpace(strings.ReplaceAll(tmx.Layer.Data.CSV, "\n", "")), ",")
+	if len(values) != 13*9 {
+		t.Fatalf("RenderTMX: want %d tiles, got %d", 13*9, len(values))
+	}
+	for row, tiles := range r.ToGrid() {
+		for col, tile := range tiles {
+			want := 2
+			if tile == 1 {
+				want = 7
+			}
+			if got, err := strconv.Atoi(values[row*13+col]); err != nil || got != want {
+				t.Fatalf("RenderTMX: tile (%d, %d): want %d, got %q", row, col, want, values[row*13+col])
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+// RectangleToroidalMaze creates a maze using Wilson's algorithm on a grid that wraps around,
+// so the western edge is connected to the eastern edge and the northern edge to the southern edge.
+// a passage that wraps is shown as a gap in the outer wall on both edges.
+// the entrance and exit are flagged but no outer wall is opened for them, since there are no outer walls.
+// it returns an error if the grid is smaller than 3 x 3.
+func RectangleToroidalMaze(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, WilsonGenerator{}, solve, withToroidal())
+}
+
+// createToroidalGrid creates a new rectangular grid where the cells on each edge
+// are linked to the cells on the opposite edge.
+func createToroidalGrid(height, width int) *grid {
+	g := createGrid(height, width)
+	for row := 0; row < height; row++ {
+		west, east := g.cells[row][0], g.cells[row][width-1]
+		west.neighbors.west, east.neighbors.east = east, west
+		west.neighborhood = append(west.neighborhood, east)
+		east.neighborhood = append(east.neighborhood, west)
+	}
+	for col := 0; col < width; col++ {
+		north, south := g.cells[0][col], g.cells[height-1][col]
+		north.neighbors.north, south.neighbors.south = south, north
+		north.neighborhood = append(north.neighborhood, south)
+		south.neighborhood = append(south.neighborhood, north)
+	}
+	return g
+}
+
+// wraps returns true if the grid is toroidal. only a toroidal grid has neighbors beyond its edges.
+func (g *grid) wraps() bool {
+	return g.cells[0][0].neighbors.north != nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToroidalSolveWraps(t *testing.T) {
+	// the entrance and exit are at opposite ends of the top row. the only passage between them
+	// wraps around from the western edge to the eastern edge.
+	g := createToroidalGrid(3, 3)
+	g.cells[0][0].linkTo(g.cells[0][2])
+	g.cells[0][0].linkTo(g.cells[1][0])
+	g.cells[0][2].linkTo(g.cells[1][2])
+	r := &Rectangle{g: g}
+	if err := r.SetEntrance(0, 0); err != nil {
+		t.Fatalf("SetEntrance: %v", err)
+	} else if err = r.SetExit(0, 2); err != nil {
+		t.Fatalf("SetExit: %v", err)
+	}
+	for _, solve := range []func() error{r.Solve, r.SolveBFS, r.SolveAStar} {
+		if err := solve(); err != nil {
+			t.Fatalf("solve: %v", err)
+		}
+		if want, got := [][2]int{{0, 0}, {0, 2}}, r.SolutionPath(); !reflect.DeepEqual(got, want) {
+			t.Errorf("SolutionPath: want %v, got %v", want, got)
+		}
+	}
+	// the wrapped passage shows as gaps in the outer walls on both edges
+	if g.cells[0][0].walls.west || g.cells[0][2].walls.east {
+		t.Errorf("wrap: want the western and eastern walls open")
+	}
+}
+
+func TestRectangleToroidalMaze(t *testing.T) {
+	wraps := 0
+	for seed := int64(1); seed <= 5; seed++ {
+		r, err := RectangleMazeWith(6, 8, WilsonGenerator{}, true, withToroidal(), WithSeed(seed))
+		if err != nil {
+			t.Fatalf("RectangleMazeWith: %v", err)
+		}
+		if !r.IsPerfect() {
+			t.Errorf("seed %d: toroidal maze is not perfect", seed)
+		}
+		for _, c := range r.g.allCells() {
+			for _, neighbor := range c.openNeighbors() {
+				if _, ok := c.wrapsTo(neighbor); ok {
+					wraps++
+				}
+			}
+		}
+	}
+	if wraps == 0 {
+		t.Errorf("RectangleToroidalMaze: want passages that wrap, got none")
+	}
+	if _, err := RectangleToroidalMaze(2, 8, false); err == nil {
+		t.Errorf("RectangleToroidalMaze: 2 x 8: want error, got nil")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+// disjointSet is a union-find structure over the integers 0 to n-1.
+// it uses path compression and union by rank.
+type disjointSet struct {
+	parent []int
+	rank   []int
+}
+
+// newDisjointSet creates a disjoint set where every element is in its own set.
+func newDisjointSet(n int) *disjointSet {
+	ds := &disjointSet{
+		parent: make([]int, n),
+		rank:   make([]int, n),
+	}
+	for i := range ds.parent {
+		ds.parent[i] = i
+	}
+	return ds
+}
+
+// find returns the representative element of the set containing x.
+func (ds *disjointSet) find(x int) int {
+	for ds.parent[x] != x {
+		// point x at its grandparent to flatten the tree as we go
+		ds.parent[x] = ds.parent[ds.parent[x]]
+		x = ds.parent[x]
+	}
+	return x
+}
+
+// union merges the sets containing a and b.
+// it returns false if they were already in the same set.
+func (ds *disjointSet) union(a, b int) bool {
+	a, b = ds.find(a), ds.find(b)
+	if a == b {
+		return false
+	}
+	if ds.rank[a] < ds.rank[b] {
+		a, b = b, a
+	}
+	ds.parent[b] = a
+	if ds.rank[a] == ds.rank[b] {
+		ds.rank[a]++
+	}
+	return true
+}
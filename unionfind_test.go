@@ -0,0 +1,34 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "testing"
+
+func TestDisjointSet(t *testing.T) {
+	ds := newDisjointSet(8)
+	for n := 0; n < 8; n++ {
+		if ds.find(n) != n {
+			t.Errorf("find(%d): want every element in its own set, got %d", n, ds.find(n))
+		}
+	}
+
+	// join the even numbers into one set and 1, 3, and 5 into another, leaving 7 on its own
+	for _, pair := range [][2]int{{0, 2}, {4, 6}, {2, 6}, {1, 3}, {5, 3}} {
+		if !ds.union(pair[0], pair[1]) {
+			t.Errorf("union(%d, %d): want true for separate sets, got false", pair[0], pair[1])
+		}
+	}
+	for _, pair := range [][2]int{{0, 6}, {4, 2}, {1, 5}} {
+		if ds.union(pair[0], pair[1]) {
+			t.Errorf("union(%d, %d): want false for the same set, got true", pair[0], pair[1])
+		}
+	}
+	for a := 0; a < 8; a++ {
+		for b := 0; b < 8; b++ {
+			want := a == b || (a%2 == 0 && b%2 == 0) || (a%2 == 1 && b%2 == 1 && a != 7 && b != 7)
+			if got := ds.find(a) == ds.find(b); got != want {
+				t.Errorf("find(%d) == find(%d): want %v, got %v", a, b, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import "math/rand"
+
+// RectangleWeave creates a weave maze, where some passages tunnel beneath others.
+// the crossings are placed at random first, then the rest of the maze is carved with Kruskal's algorithm.
+// a crossing cell is a bridge: its own passage runs straight across it and the tunnel runs beneath it
+// at right angles, between the two sides of the cell that still have walls.
+func RectangleWeave(height, width int, solve bool) (*Rectangle, error) {
+	return RectangleMazeWith(height, width, WeaveGenerator{}, solve)
+}
+
+// carveWeave places crossings at random and then carves the rest of the grid with Kruskal's algorithm.
+// the sets track connectivity through the tunnels too, so the result is still a perfect maze.
+func (g *grid) carveWeave(rng *rand.Rand) {
+	sets := newDisjointSet(g.height * g.width)
+	id := func(c *cell) int {
+		return c.row*g.width + c.col
+	}
+
+	// try to add a crossing at every cell, in a random order
+	candidates := g.allCells()
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	for _, c := range candidates[:len(candidates)/2] {
+		n, e, s, w := c.neighbors.north, c.neighbors.east, c.neighbors.south, c.neighbors.west
+		// crossings need all four neighbors, and neighbors can't be crossings themselves
+		if n == nil || e == nil || s == nil || w == nil {
+			continue
+		} else if c.in || n.under || e.under || s.under || w.under {
+			continue
+		}
+		// the bridge and the tunnel must not connect cells that are already connected
+		if sets.find(id(n)) == sets.find(id(s)) || sets.find(id(e)) == sets.find(id(w)) ||
+			sets.find(id(c)) == sets.find(id(e)) || sets.find(id(c)) == sets.find(id(w)) ||
+			sets.find(id(n)) == sets.find(id(e)) || sets.find(id(n)) == sets.find(id(w)) ||
+			sets.find(id(s)) == sets.find(id(e)) || sets.find(id(s)) == sets.find(id(w)) {
+			continue
+		}
+
+		// pick the direction of the bridge at random; the tunnel runs the other way
+		over, under := [2]*cell{n, s}, [2]*cell{w, e}
+		if rng.Intn(2) == 0 {
+			over, under = under, over
+		}
+		c.linkTo(over[0])
+		c.linkTo(over[1])
+		sets.union(id(c), id(over[0]))
+		sets.union(id(c), id(over[1]))
+		// open the walls of the tunnel entrances on the neighbors, but not on the crossing cell
+		for _, neighbor := range under {
+			for _, dir := range []Direction{North, East, South, West} {
+				if neighbor.neighbor(dir) == c {
+					neighbor.setWall(dir, false)
+				}
+			}
+		}
+		sets.union(id(under[0]), id(under[1]))
+		c.under = true
+		c.in, n.in, e.in, s.in, w.in = true, true, true, true, true
+	}
+
+	// carve the rest of the maze with Kruskal's algorithm, leaving the walls of the crossings alone
+	var walls [][2]*cell
+	for _, c := range g.allCells() {
+		if c.neighbors.east != nil {
+			walls = append(walls, [2]*cell{c, c.neighbors.east})
+		}
+		if c.neighbors.south != nil {
+			walls = append(walls, [2]*cell{c, c.neighbors.south})
+		}
+	}
+	rng.Shuffle(len(walls), func(i, j int) {
+		walls[i], walls[j] = walls[j], walls[i]
+	})
+	for _, wall := range walls {
+		a, b := wall[0], wall[1]
+		if a.under || b.under {
+			continue
+		}
+		if sets.union(id(a), id(b)) {
+			a.linkTo(b)
+		}
+		a.in, b.in = true, true
+	}
+}
+
+// hasCrossings returns true if any cell in the grid has a tunnel beneath it.
+// the layouts that give each wall a single square, like ToGrid, can't show a tunnel,
+// so they refuse weave mazes instead of drawing the crossings as junctions.
+func (g *grid) hasCrossings() bool {
+	for _, c := range g.allCells() {
+		if c.under {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// weaveMaze returns a weave maze that has at least one crossing.
+func weaveMaze(t *testing.T) *Rectangle {
+	t.Helper()
+	r, err := RectangleMazeWithSeed(8, 8, WeaveGenerator{}, 42, false)
+	if err != nil {
+		t.Fatalf("RectangleMazeWithSeed: %v", err)
+	} else if !r.g.hasCrossings() {
+		t.Fatalf("RectangleMazeWithSeed: weave maze has no crossings")
+	}
+	return r
+}
+
+// checkCrossings fails the test unless every crossing has a bridge straight across it
+// and a tunnel that leads straight through it at right angles.
+func checkCrossings(t *testing.T, r *Rectangle) {
+	t.Helper()
+	for _, c := range r.g.allCells() {
+		if !c.under {
+			continue
+		}
+		walls := 0
+		for _, dir := range searchOrder {
+			if !c.wall(dir) {
+				continue
+			}
+			walls++
+			// the tunnel must lead straight through the crossing from both sides
+			if c.neighbor(dir).step(dir.opposite()) != c.neighbor(dir.opposite()) {
+				t.Errorf("crossing (%d, %d): no tunnel to the %s", c.row, c.col, dir)
+			}
+		}
+		if walls != 2 || c.wall(North) != c.wall(South) {
+			t.Errorf("crossing (%d, %d): want walls on two opposite sides, got %d", c.row, c.col, walls)
+		}
+	}
+}
+
+func TestRectangleWeave(t *testing.T) {
+	r := weaveMaze(t)
+	checkCrossings(t, r)
+	if err := r.Solve(); err != nil {
+		t.Errorf("Solve: %v", err)
+	}
+}
+
+func TestWeaveJSONRoundTrip(t *testing.T) {
+	r := weaveMaze(t)
+	if err := r.Solve(); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	loaded := roundTripJSON(t, r)
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			want, got := r.g.cells[row][col], loaded.g.cells[row][col]
+			if got.walls != want.walls || got.under != want.under {
+				t.Errorf("cell (%d, %d): want walls %v under %v, got walls %v under %v",
+					row, col, want.walls, want.under, got.walls, got.under)
+			}
+		}
+	}
+	want, got := r.SolutionPath(), loaded.SolutionPath()
+	if len(got) != len(want) {
+		t.Fatalf("SolutionPath: want %v, got %v", want, got)
+	}
+	for n := range want {
+		if got[n] != want[n] {
+			t.Fatalf("SolutionPath: want %v, got %v", want, got)
+		}
+	}
+
+	var wantSVG, gotSVG bytes.Buffer
+	if err := r.RenderSVG(&wantSVG, 10); err != nil {
+		t.Fatalf("RenderSVG: %v", err)
+	} else if err = loaded.RenderSVG(&gotSVG, 10); err != nil {
+		t.Fatalf("RenderSVG: %v", err)
+	} else if wantSVG.String() != gotSVG.String() {
+		t.Errorf("RenderSVG: loaded maze renders differently")
+	}
+}
+
+func TestWeaveUnsupportedRenderers(t *testing.T) {
+	r := weaveMaze(t)
+	if grid := r.ToGrid(); grid != nil {
+		t.Errorf("ToGrid: want nil, got %d rows", len(grid))
+	}
+	if err := r.RenderTMX(io.Discard, 1, 2); err == nil {
+		t.Errorf("RenderTMX: want error, got nil")
+	}
+	if err := r.RenderBlockPNG(io.Discard, 4, 2); err == nil {
+		t.Errorf("RenderBlockPNG: want error, got nil")
+	}
+	if err := r.WriteBinary(io.Discard); err == nil {
+		t.Errorf("WriteBinary: want error, got nil")
+	}
+}
+
+func TestTileMazesKeepsCrossings(t *testing.T) {
+	r := weaveMaze(t)
+	tiled, err := TileMazes([][]*Rectangle{{r, r}})
+	if err != nil {
+		t.Fatalf("TileMazes: %v", err)
+	}
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 16; col++ {
+			if want, got := r.g.cells[row][col%8].under, tiled.g.cells[row][col].under; got != want {
+				t.Errorf("cell (%d, %d): under: want %v, got %v", row, col, want, got)
+			}
+		}
+	}
+	if err := tiled.Solve(); err != nil {
+		t.Errorf("Solve: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"context"
+	"math/rand"
+)
+
+// wilsonGraph describes a graph of cells for Wilson's algorithm, so that the rectangular, hex,
+// and polar grids can share one implementation of it. T is the type of a cell pointer.
+type wilsonGraph[T comparable] struct {
+	// cells is every cell that should be carved, in a fixed order
+	cells []T
+	// in returns true if the cell has been added to the maze
+	in func(c T) bool
+	// add links the cell to the next cell of the walk and adds it to the maze.
+	// to is the zero value for the first cell, which is added without a passage.
+	add func(from, to T)
+	// neighbor picks the next step of a random walk from the cell
+	neighbor func(c T) T
+	// next and setNext keep the walk pointers, which the cells store for us
+	next    func(c T) T
+	setNext func(c, next T)
+}
+
+// carve runs Wilson's algorithm over the graph, shuffling the cells with rng.
+// cells that are already in the maze are left as is; if there are none, a random cell is added first.
+// it checks the context periodically and returns its error if it has been cancelled.
+func (wg wilsonGraph[T]) carve(ctx context.Context, rng *rand.Rand) error {
+	// steps counts the moves in the walks so that we don't check the context too often
+	steps := 0
+	// create a stack containing all the cells in the grid in a random order
+	stack := append([]T{}, wg.cells...)
+	rng.Shuffle(len(stack), func(i, j int) {
+		stack[i], stack[j] = stack[j], stack[i]
+	})
+
+	// randomly add a cell to the maze if none have been added yet.
+	// since the stack contains all cells in a random order, we can just pop the first cell from it
+	// and mark it as in.
+	hasCellsIn := false
+	for _, c := range stack {
+		if wg.in(c) {
+			hasCellsIn = true
+			break
+		}
+	}
+	if !hasCellsIn {
+		var none T
+		wg.add(stack[0], none)
+		stack = stack[1:]
+	}
+
+	// while the stack is not empty, pop a cell.
+	// perform a random walk from that cell, stopping only when we encounter a cell that is already in the maze.
+	// for every cell that we visit, we record the direction that we exited so that we'll be able to retrace our path.
+	for len(stack) != 0 {
+		// pick a cell at random from the stack.
+		// since the stack is randomly shuffled before we start, we can just pop the first cell.
+		from := stack[0]
+		stack = stack[1:]
+
+		// check for cancellation before starting the walk
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// randomly walk until we find a cell that is already in the maze.
+		// the walk pointers from earlier walks are overwritten as we go, so loops are erased
+		// without clearing the whole grid, which would make carving quadratic in the number of cells.
+		for to := from; !wg.in(to); {
+			// check for cancellation every so often
+			if steps++; steps%1024 == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			// pick a neighboring cell at random and move to it
+			wg.setNext(to, wg.neighbor(to))
+			to = wg.next(to)
+		}
+
+		// retrace the walk, removing walls as needed, until we find a cell that is in the maze
+		for !wg.in(from) {
+			to := wg.next(from)
+			wg.add(from, to)
+			// walk to the next cell
+			from = to
+		}
+	}
+
+	return ctx.Err()
+}
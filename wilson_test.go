@@ -0,0 +1,82 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package maze
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// spanningTree reports whether the passages connect every cell without forming a loop.
+// a graph with one passage fewer than cells is a tree exactly when it is connected.
+func spanningTree[T comparable](cells []T, links func(c T) []T) bool {
+	if len(cells) == 0 {
+		return true
+	}
+	passages, seen := 0, map[T]bool{cells[0]: true}
+	for queue := []T{cells[0]}; len(queue) != 0; queue = queue[1:] {
+		for _, next := range links(queue[0]) {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	for _, c := range cells {
+		passages += len(links(c))
+	}
+	return len(seen) == len(cells) && passages/2 == len(cells)-1
+}
+
+func TestHexMazeIsSpanningTree(t *testing.T) {
+	h, err := HexMaze(12, 15)
+	if err != nil {
+		t.Fatalf("HexMaze: %v", err)
+	}
+	var cells []*hexCell
+	for _, row := range h.g.cells {
+		cells = append(cells, row...)
+	}
+	links := func(c *hexCell) (open []*hexCell) {
+		for dir, neighbor := range c.neighbors {
+			if neighbor != nil && !c.walls[dir] {
+				open = append(open, neighbor)
+			}
+		}
+		return open
+	}
+	if !spanningTree(cells, links) {
+		t.Errorf("HexMaze: passages do not form a spanning tree")
+	}
+}
+
+func TestPolarMazeIsSpanningTree(t *testing.T) {
+	p, err := PolarMaze(8, false)
+	if err != nil {
+		t.Fatalf("PolarMaze: %v", err)
+	}
+	var cells []*polarCell
+	for _, ring := range p.g.cells {
+		cells = append(cells, ring...)
+	}
+	links := func(c *polarCell) (open []*polarCell) {
+		for neighbor := range c.links {
+			open = append(open, neighbor)
+		}
+		return open
+	}
+	if !spanningTree(cells, links) {
+		t.Errorf("PolarMaze: passages do not form a spanning tree")
+	}
+}
+
+func TestWilsonGraphCancelled(t *testing.T) {
+	g := createGrid(10, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := g.carveWilsonContext(ctx, rand.New(rand.NewSource(1))); !errors.Is(err, context.Canceled) {
+		t.Errorf("carveWilsonContext: want %v, got %v", context.Canceled, err)
+	}
+}